@@ -3,58 +3,45 @@ package analyzer
 import (
 	"backend/commands"
 	"backend/stores"
-	"errors"
 	"fmt"
-	"strings"
 )
 
-// Analyzer analiza el comando de entrada y ejecuta la acción correspondiente
+// Analyzer analiza el comando de entrada y lo ejecuta con la sesión por defecto (terminal)
 func Analyzer(input string) (string, error) {
-	// Eliminar espacios en blanco al inicio/final
-	input = strings.TrimSpace(input)
+	return AnalyzerWithSession(input, stores.Auth)
+}
 
-	// Ignorar comentarios o líneas vacías
-	if input == "" || strings.HasPrefix(input, "#") {
-		return "", nil
+// AnalyzerWithSession analiza el comando de entrada y ejecuta la acción correspondiente
+// con la sesión indicada, que es la que usan login, logout y los comandos de archivos
+func AnalyzerWithSession(input string, session *stores.AuthStore) (string, error) {
+	_, result, err := Execute(input, session)
+	return result.Message, err
+}
+
+// Execute analiza y ejecuta una línea con la sesión indicada, y devuelve el nombre del
+// comando junto con su resultado estructurado. Una línea vacía o de comentario devuelve "".
+func Execute(input string, session *stores.AuthStore) (string, commands.Result, error) {
+	// Separar el nombre del comando y sus parámetros, respetando comillas y comentarios
+	name, params, args, err := Lex(input)
+	if err != nil {
+		return "", commands.Result{}, err
 	}
 
-	// Tokenizar por espacios
-	tokens := strings.Fields(input)
-	if len(tokens) == 0 {
-		return "", errors.New("no se proporcionó ningún comando válido")
+	// Ignorar comentarios o líneas vacías
+	if name == "" {
+		return "", commands.Result{}, nil
 	}
 
-	// Identificar y ejecutar comando
-	switch strings.ToLower(tokens[0]) {
-	case "mkdisk":
-		return commands.ParseMkdisk(tokens[1:])
-	case "rmdisk":
-		return commands.ParseRmdisk(tokens[1:])
-	case "fdisk":
-		return commands.ParseFdisk(tokens[1:])
-	case "mount":
-		return commands.ParseMount(tokens[1:])
-	case "mkfs":
-		return commands.ParseMkfs(tokens[1:])
-	case "rep":
-		return commands.ParseRep(tokens[1:])
-	case "mkdir":
-		return commands.ParseMkdir(tokens[1:])
-	case "login":
-		return commands.ParseLogin(tokens[1:])
-	case "logout":
-		return commands.ParseLogout(tokens[1:])	
-	case "rmgrp":
-		return commands.ParseRmgrp(tokens[1:])
-	case "mkgrp":
-		return commands.ParseMkgrp(tokens[1:])	
-	case "cat":
-		return commands.ParseCat(tokens[1:])
-		
-	
-	case "mounted":
-		return stores.ShowMountedPartitions(), nil
-	default:
-		return "", fmt.Errorf("comando desconocido: %s", tokens[0])
+	// Buscar el comando en el registro y validar sus parámetros contra lo que declara
+	spec, ok := commands.LookupCommand(name)
+	if !ok {
+		return name, commands.Result{}, fmt.Errorf("comando desconocido: %s", name)
+	}
+	params, err = spec.Validate(params, args)
+	if err != nil {
+		return name, commands.Result{}, err
 	}
+
+	result, err := spec.Handler(params, session)
+	return name, result, err
 }
@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"backend/commands"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// token es una palabra de la línea ya sin comillas. eq es la posición del primer "="
+// que estaba fuera de comillas, o -1 si no tiene.
+type token struct {
+	text string
+	eq   int
+}
+
+// Lex separa una línea en el nombre del comando, sus parámetros y sus argumentos posicionales.
+//
+//	mkdisk -size=5 -path="/home/mis discos/D.mia"   # comentario
+//
+// Los valores pueden ir entre comillas dobles (con \" y \\ como escapes), los nombres no
+// distinguen mayúsculas, los parámetros sin "=" son banderas (-p, -r) y lo que sigue a un
+// # al inicio de una palabra es comentario. Las palabras que no empiezan con guion se
+// devuelven aparte, en orden (ej: help mkdisk). Una línea vacía o de comentario devuelve "".
+func Lex(input string) (string, commands.Params, []string, error) {
+	tokens, err := splitTokens(input)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return "", nil, nil, nil
+	}
+
+	name := tokens[0].text
+	if strings.HasPrefix(name, "-") || tokens[0].eq != -1 {
+		return "", nil, nil, fmt.Errorf("se esperaba el nombre del comando y se encontró: %s", name)
+	}
+
+	params := commands.Params{}
+	var args []string
+	for _, tok := range tokens[1:] {
+		if !strings.HasPrefix(tok.text, "-") {
+			args = append(args, tok.text)
+			continue
+		}
+
+		key, param := tok.text[1:], commands.Param{Flag: true}
+		if tok.eq != -1 {
+			key, param = tok.text[1:tok.eq], commands.Param{Value: tok.text[tok.eq+1:]}
+		}
+
+		key = strings.ToLower(key)
+		if !isParamName(key) {
+			return "", nil, nil, fmt.Errorf("parámetro inválido: %s", tok.text)
+		}
+		if params.Has(key) {
+			return "", nil, nil, fmt.Errorf("parámetro repetido: -%s", key)
+		}
+		params[key] = param
+	}
+
+	return strings.ToLower(name), params, args, nil
+}
+
+// splitTokens separa la línea en palabras respetando comillas y quitando el comentario final
+func splitTokens(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		if runes[i] == '#' {
+			break
+		}
+
+		var text strings.Builder
+		eq := -1
+		inQuotes := false
+		for ; i < len(runes); i++ {
+			r := runes[i]
+			if inQuotes {
+				if r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+					text.WriteRune(runes[i])
+				} else if r == '"' {
+					inQuotes = false
+				} else {
+					text.WriteRune(r)
+				}
+				continue
+			}
+
+			if unicode.IsSpace(r) {
+				break
+			}
+			switch {
+			case r == '"':
+				inQuotes = true
+			case r == '\\' && i+1 < len(runes) && runes[i+1] == '"':
+				i++
+				text.WriteRune('"')
+			case r == '=' && eq == -1:
+				eq = text.Len()
+				text.WriteRune(r)
+			default:
+				text.WriteRune(r)
+			}
+		}
+
+		if inQuotes {
+			return nil, errors.New("comillas sin cerrar en el comando")
+		}
+		tokens = append(tokens, token{text: text.String(), eq: eq})
+	}
+
+	return tokens, nil
+}
+
+// isParamName verifica que el nombre solo tenga letras, números o guion bajo
+func isParamName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"backend/commands"
+)
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		input  string
+		name   string
+		params commands.Params
+		args   []string
+	}{
+		{
+			input:  `mkdisk -size=5 -path="/home/mis discos/D.mia"`,
+			name:   "mkdisk",
+			params: commands.Params{"size": {Value: "5"}, "path": {Value: "/home/mis discos/D.mia"}},
+		},
+		{
+			input:  `MKDIR -P -Path=/home/a`,
+			name:   "mkdir",
+			params: commands.Params{"p": {Flag: true}, "path": {Value: "/home/a"}},
+		},
+		{
+			input:  `mkdir -path="/a \"b\" \\c"`,
+			name:   "mkdir",
+			params: commands.Params{"path": {Value: `/a "b" \c`}},
+		},
+		{
+			input:  `cat -file1=a\"b`,
+			name:   "cat",
+			params: commands.Params{"file1": {Value: `a"b`}},
+		},
+		{
+			input:  `rep -id=781A -name=mbr # -path=/ignorado.png`,
+			name:   "rep",
+			params: commands.Params{"id": {Value: "781A"}, "name": {Value: "mbr"}},
+		},
+		{
+			input:  `mkdir -path=/a#b -p`,
+			name:   "mkdir",
+			params: commands.Params{"path": {Value: "/a#b"}, "p": {Flag: true}},
+		},
+		{
+			input:  `login -pass="x=y" -user=root`,
+			name:   "login",
+			params: commands.Params{"pass": {Value: "x=y"}, "user": {Value: "root"}},
+		},
+		{
+			input:  `help mkdisk`,
+			name:   "help",
+			params: commands.Params{},
+			args:   []string{"mkdisk"},
+		},
+		{input: `   # solo un comentario`},
+		{input: ``},
+	}
+
+	for _, tt := range tests {
+		name, params, args, err := Lex(tt.input)
+		if err != nil {
+			t.Errorf("Lex(%q): %v", tt.input, err)
+			continue
+		}
+		if name != tt.name || !reflect.DeepEqual(params, tt.params) || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("Lex(%q) = %q %+v %q, se esperaba %q %+v %q", tt.input, name, params, args, tt.name, tt.params, tt.args)
+		}
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	inputs := []string{
+		`mkdisk -path="/sin cerrar`,
+		`-size=5`,
+		`mkdisk=1`,
+		`mkdisk -size=5 -SIZE=6`,
+		`mkdisk -=5`,
+		`mkdisk -si.ze=5`,
+	}
+	for _, input := range inputs {
+		if _, _, _, err := Lex(input); err == nil {
+			t.Errorf("Lex(%q) debía fallar", input)
+		}
+	}
+}
@@ -1,42 +1,46 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"backend/stores"
 	"backend/structures"
+	"backend/utils"
 )
 
-func ParseCat(tokens []string) (string, error) {
-	if !stores.Auth.IsAuthenticated() {
-		return "", fmt.Errorf("Error: debe iniciar sesión primero.")
+func ParseCat(params Params, session *stores.AuthStore) (Result, error) {
+	info := session.Info()
+	if !info.IsLoggedIn {
+		return Result{}, errors.New("debe iniciar sesión para ejecutar este comando")
 	}
 
-	// Convertir tokens a mapa de args
+	// Solo llegan parámetros -file1, -file2, ... ya validados por el registro
 	args := make(map[string]string)
-	for _, token := range tokens {
-		kv := strings.SplitN(token, "=", 2)
-		if len(kv) == 2 {
-			key := strings.ToLower(strings.TrimSpace(kv[0]))
-			value := strings.Trim(strings.TrimSpace(kv[1]), "\"")
-			args[key] = value
-		}
+	for _, key := range params.Names() {
+		args[key] = params.Value(key)
+	}
+
+	unlock, err := stores.RLockPartition(info.PartitionID)
+	if err != nil {
+		return Result{}, err
 	}
+	defer unlock()
 
 	// Obtener superbloque de la sesión activa
-	sb, _, path, err := stores.GetMountedPartitionSuperblock(stores.Auth.PartitionID)
+	sb, _, path, err := stores.GetMountedPartitionSuperblock(info.PartitionID)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
 	// Ejecutar lectura de archivos y devolver resultado
-	return ExecuteCatCommand(args, path, *sb)
+	return ExecuteCatCommand(session, args, path, *sb)
 }
 
-func ExecuteCatCommand(args map[string]string, path string, sb structures.SuperBlock) (string, error) {
+func ExecuteCatCommand(session *stores.AuthStore, args map[string]string, path string, sb structures.SuperBlock) (Result, error) {
 	var output strings.Builder
+	var files []CatFile
 
 	for i := 1; ; i++ {
 		param := fmt.Sprintf("file%d", i)
@@ -45,91 +49,57 @@ func ExecuteCatCommand(args map[string]string, path string, sb structures.SuperB
 			break
 		}
 
-		// Buscar el inodo del archivo
-		inodeIndex, err := structures.FindInodeByPath(path, filePath, sb)
-		if err != nil {
-			output.WriteString(fmt.Sprintf("Error: El archivo '%s' no existe.\n", filePath))
-			continue
-		}
-
-		// Leer inodo
-		var inode structures.Inode
-		err = inode.Deserialize(path, int64(sb.S_inode_start+(inodeIndex*sb.S_inode_size)))
+		content, err := readCatFile(session, path, filePath, sb)
 		if err != nil {
-			output.WriteString(fmt.Sprintf("Error: No se pudo leer el inodo del archivo '%s'.\n", filePath))
-			continue
-		}
-
-		// Verificar permisos de lectura
-		if !HasReadPermission(inode) {
-			output.WriteString(fmt.Sprintf("Error: No tiene permiso de lectura en '%s'.\n", filePath))
-			continue
-		}
-
-		// Leer contenido del archivo
-		content, err := ReadFileContent(path, inode, sb)
-		if err != nil {
-			output.WriteString(fmt.Sprintf("Error al leer el archivo '%s'.\n", filePath))
+			output.WriteString("Error: " + err.Error() + "\n")
+			files = append(files, CatFile{Path: filePath, Error: err.Error()})
 			continue
 		}
 
 		// Agregar contenido al resultado
-		output.WriteString(string(content) + "\n")
+		output.WriteString(content + "\n")
+		files = append(files, CatFile{Path: filePath, Content: content})
 	}
 
 	// Si no hubo nada que imprimir
 	if output.Len() == 0 {
-		return "CAT: No se pudo mostrar ningún archivo.\n", nil
+		return Result{Message: "CAT: No se pudo mostrar ningún archivo.\n", Data: files}, nil
 	}
 
-	return "========================== CAT ===============================\n" +
-		"Contenido de archivos:\n\n" +
-		output.String() +
-		"==============================================================\n", nil
+	return Result{
+		Message: "========================== CAT ===============================\n" +
+			"Contenido de archivos:\n\n" +
+			output.String() +
+			"==============================================================\n",
+		Data: files,
+	}, nil
 }
 
-func HasReadPermission(inode structures.Inode) bool {
-	perm := string(inode.I_perm[:])
-	username, _, _ := stores.Auth.GetCurrentUser()
-
-	// root siempre tiene permiso
-	if username == "root" {
-		return true
-	}
-
-	var accessChar byte
-	if int(inode.I_uid) == stores.Auth.UserID {
-		accessChar = perm[0]
-	} else if int(inode.I_gid) == stores.Auth.GroupID {
-		accessChar = perm[1]
-	} else {
-		accessChar = perm[2]
+// readCatFile lee un archivo para cat verificando que exista y que la sesión pueda leerlo
+func readCatFile(session *stores.AuthStore, path string, filePath string, sb structures.SuperBlock) (string, error) {
+	// Buscar el inodo del archivo
+	inodeIndex, err := structures.FindInodeByPath(path, filePath, sb)
+	if err != nil {
+		return "", fmt.Errorf("el archivo '%s' no existe", filePath)
 	}
 
-	return accessChar == '4' || accessChar == '6' || accessChar == '7'
-}
-
-func ReadFileContent(path string, inode structures.Inode, sb structures.SuperBlock) ([]byte, error) {
-	file, err := os.Open(path)
+	// Leer inodo
+	var inode structures.Inode
+	err = inode.Deserialize(path, int64(sb.S_inode_start+(inodeIndex*sb.S_inode_size)))
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("no se pudo leer el inodo del archivo '%s'", filePath)
 	}
-	defer file.Close()
 
-	var content []byte
-	for _, blockIndex := range inode.I_block {
-		if blockIndex == -1 {
-			continue
-		}
+	// Verificar permisos de lectura
+	if !utils.HasReadPermission(session, inode) {
+		return "", fmt.Errorf("no tiene permiso de lectura en '%s'", filePath)
+	}
 
-		offset := sb.S_block_start + int32(blockIndex)*sb.S_block_size
-		var block structures.FileBlock
-		err := block.Deserialize(path, int64(offset))
-		if err != nil {
-			return nil, err
-		}
-		content = append(content, block.B_content[:]...)
+	// Leer contenido del archivo con todos sus bloques, hasta I_size
+	content, err := sb.ReadFile(path, &inode)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el archivo '%s'", filePath)
 	}
 
-	return content, nil
+	return string(content), nil
 }
@@ -1,13 +1,15 @@
 package commands
 
 import (
+	common "backend/common"
+	stores "backend/stores"
 	structures "backend/structures"
 	utils "backend/utils"
-	"errors"  // Paquete para manejar errores y crear nuevos errores con mensajes personalizados
-	"fmt"     // Paquete para formatear cadenas y realizar operaciones de entrada/salida
-	"regexp"  // Paquete para trabajar con expresiones regulares, útil para encontrar y manipular patrones en cadenas
-	"strconv" // Paquete para convertir cadenas a otros tipos de datos, como enteros
-	"strings" // Paquete para manipular cadenas, como unir, dividir, y modificar contenido de cadenas
+	"errors"        // Paquete para manejar errores y crear nuevos errores con mensajes personalizados
+	"fmt"           // Paquete para formatear cadenas y realizar operaciones de entrada/salida
+	"path/filepath" // Paquete para limpiar la ruta del disco
+	"strconv"       // Paquete para convertir cadenas a otros tipos de datos, como enteros
+	"strings"       // Paquete para manipular cadenas, como unir, dividir, y modificar contenido de cadenas
 )
 
 // FDISK estructura que representa el comando fdisk con sus parámetros
@@ -27,111 +29,26 @@ type FDISK struct {
 */
 
 // CommandFdisk parsea el comando fdisk y devuelve una instancia de FDISK
-func ParseFdisk(tokens []string) (string, error) {
-	cmd := &FDISK{} // Crea una nueva instancia de FDISK
-
-	// Unir tokens en una sola cadena y luego dividir por espacios, respetando las comillas
-	args := strings.Join(tokens, " ")
-	// Expresión regular para encontrar los parámetros del comando fdisk
-	re := regexp.MustCompile(`-size=\d+|-unit=[kKmM]|-fit=[bBfF]{2}|-path="[^"]+"|-path=[^\s]+|-type=[pPeElL]|-name="[^"]+"|-name=[^\s]+`)
-	// Encuentra todas las coincidencias de la expresión regular en la cadena de argumentos
-	matches := re.FindAllString(args, -1)
-
-	// Itera sobre cada coincidencia encontrada
-	for _, match := range matches {
-		// Divide cada parte en clave y valor usando "=" como delimitador
-		kv := strings.SplitN(match, "=", 2)
-		if len(kv) != 2 {
-			return "", fmt.Errorf("formato de parámetro inválido: %s", match)
-		}
-		key, value := strings.ToLower(kv[0]), kv[1]
-
-		// Remove quotes from value if present
-		if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-			value = strings.Trim(value, "\"")
-		}
-
-		// Switch para manejar diferentes parámetros
-		switch key {
-		case "-size":
-			// Convierte el valor del tamaño a un entero
-			size, err := strconv.Atoi(value)
-			if err != nil || size <= 0 {
-				return "", errors.New("el tamaño debe ser un número entero positivo")
-			}
-			cmd.size = size
-		case "-unit":
-			// Verifica que la unidad sea "K" o "M"
-			if value != "K" && value != "M" {
-				return "", errors.New("la unidad debe ser K o M")
-			}
-			cmd.unit = strings.ToUpper(value)
-		case "-fit":
-			// Verifica que el ajuste sea "BF", "FF" o "WF"
-			value = strings.ToUpper(value)
-			if value != "BF" && value != "FF" && value != "WF" {
-				return "", errors.New("el ajuste debe ser BF, FF o WF")
-			}
-			cmd.fit = value
-		case "-path":
-			// Verifica que el path no esté vacío
-			if value == "" {
-				return "", errors.New("el path no puede estar vacío")
-			}
-			cmd.path = value
-		case "-type":
-			// Verifica que el tipo sea "P", "E" o "L"
-			value = strings.ToUpper(value)
-			if value != "P" && value != "E" && value != "L" {
-				return "", errors.New("el tipo debe ser P, E o L")
-			}
-			cmd.typ = value
-		case "-name":
-			// Verifica que el nombre no esté vacío
-			if value == "" {
-				return "", errors.New("el nombre no puede estar vacío")
-			}
-			cmd.name = value
-		default:
-			// Si el parámetro no es reconocido, devuelve un error
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	// Verifica que los parámetros -size, -path y -name hayan sido proporcionados
-	if cmd.size == 0 {
-		return "", errors.New("faltan parámetros requeridos: -size")
-	}
-	if cmd.path == "" {
-		return "", errors.New("faltan parámetros requeridos: -path")
-	}
-	if cmd.name == "" {
-		return "", errors.New("faltan parámetros requeridos: -name")
-	}
-
-	// Si no se proporcionó la unidad, se establece por defecto a "M"
-	if cmd.unit == "" {
-		cmd.unit = "M"
-	}
-
-	// Si no se proporcionó el ajuste, se establece por defecto a "FF"
-	if cmd.fit == "" {
-		cmd.fit = "WF"
-	}
-
-	// Si no se proporcionó el tipo, se establece por defecto a "P"
-	if cmd.typ == "" {
-		cmd.typ = "P"
+func ParseFdisk(params Params) (Result, error) {
+	// Los parámetros ya vienen validados y con sus valores por defecto desde el registro
+	size, _ := strconv.Atoi(params.Value("size"))
+	cmd := &FDISK{
+		size: size,
+		unit: params.Value("unit"),
+		fit:  params.Value("fit"),
+		path: filepath.Clean(params.Value("path")),
+		typ:  params.Value("type"),
+		name: params.Value("name"),
 	}
 
 	// Crear la partición con los parámetros proporcionados
 	err := commandFdisk(cmd)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
 	// Devuelve un mensaje de éxito con los detalles de la partición creada
-	return fmt.Sprintf(
+	return Result{Message: fmt.Sprintf(
 		"========================== FDISK ===============================\n"+
 		"FDISK: Partición creada exitosamente\n"+
 		"-> Path: %s\n"+
@@ -140,10 +57,15 @@ func ParseFdisk(tokens []string) (string, error) {
 		"-> Tipo: %s\n"+
 		"-> Fit: %s\n"+
 		"=================================================================\n",
-		cmd.path, cmd.name, cmd.size, cmd.unit, cmd.typ, cmd.fit), nil
+		cmd.path, cmd.name, cmd.size, cmd.unit, cmd.typ, cmd.fit),
+		Data: PartitionData{Path: cmd.path, Name: cmd.name, Size: cmd.size, Unit: cmd.unit, Type: cmd.typ, Fit: cmd.fit},
+	}, nil
 }
 
 func commandFdisk(fdisk *FDISK) error {
+	unlock := stores.LockDisk(fdisk.path)
+	defer unlock()
+
 	// Convertir el tamaño a bytes
 	sizeBytes, err := utils.ConvertToBytes(fdisk.size, fdisk.unit)
 	if err != nil {
@@ -151,6 +73,23 @@ func commandFdisk(fdisk *FDISK) error {
 		return err
 	}
 
+	// En los discos GPT todas las particiones van en la tabla GPT
+	var mbr structures.MBR
+	if err := mbr.Deserialize(fdisk.path); err != nil {
+		return fmt.Errorf("error deserializando el MBR: %v", err)
+	}
+	if mbr.IsGPT() {
+		if fdisk.typ != "P" {
+			return errors.New("los discos GPT no usan particiones extendidas ni lógicas, solo -type=P")
+		}
+		err = createGPTPartition(fdisk, sizeBytes)
+		if err != nil {
+			fmt.Println("Error creando partición GPT:", err)
+			return err
+		}
+		return nil
+	}
+
 	if fdisk.typ == "P" {
 		// Crear partición primaria
 		err = createPrimaryPartition(fdisk, sizeBytes)
@@ -386,6 +325,87 @@ func createLogicalPartition(fdisk *FDISK, sizeBytes int) error {
 }
 
 
+// createGPTPartition crea una partición en la primera entrada libre de la tabla GPT,
+// ubicándola en el espacio libre que indique el ajuste
+func createGPTPartition(fdisk *FDISK, sizeBytes int) error {
+	header, entries, err := structures.ReadGPT(fdisk.path)
+	if err != nil {
+		return fmt.Errorf("error leyendo la tabla GPT: %v", err)
+	}
+
+	// Validar nombre único
+	if existing, _ := structures.GetGPTPartitionByName(entries, fdisk.name); existing != nil {
+		return fmt.Errorf("ya existe una partición con el nombre '%s'", fdisk.name)
+	}
+
+	// Buscar una entrada libre en la tabla
+	indexEntry := -1
+	var used []common.PartPos
+	for i := range entries {
+		if entries[i].IsFree() {
+			if indexEntry == -1 {
+				indexEntry = i
+			}
+			continue
+		}
+		used = append(used, common.PartPos{
+			Start: int64(entries[i].PartStart),
+			End:   int64(entries[i].PartStart) + int64(entries[i].PartSize),
+		})
+	}
+	if indexEntry == -1 {
+		return fmt.Errorf("la tabla GPT ya tiene %d particiones", structures.GPTMaxEntries)
+	}
+
+	start := findGPTFreeSpace(used, int64(header.FirstUsable), int64(header.LastUsable)+1, int64(sizeBytes), fdisk.fit[0])
+	if start == -1 {
+		return errors.New("no hay suficiente espacio libre en el disco para la partición")
+	}
+
+	if err := entries[indexEntry].CreatePartition(int(start), sizeBytes, fdisk.fit[0], fdisk.name); err != nil {
+		return err
+	}
+
+	err = structures.WriteGPT(fdisk.path, header, entries)
+	if err != nil {
+		return fmt.Errorf("error escribiendo la tabla GPT: %v", err)
+	}
+
+	fmt.Println("Partición GPT creada exitosamente.")
+	return nil
+}
+
+// findGPTFreeSpace devuelve el inicio del espacio libre elegido según el ajuste (F, B o W), o -1 si no cabe
+func findGPTFreeSpace(used []common.PartPos, first, end, size int64, fit byte) int64 {
+	common.SortPartsByStart(used)
+
+	best := int64(-1)
+	bestSize := int64(-1)
+	cursor := first
+	// Agregar un límite al final para revisar también el último hueco
+	for _, part := range append(used, common.PartPos{Start: end, End: end}) {
+		gap := part.Start - cursor
+		if gap >= size {
+			switch fit {
+			case 'F':
+				return cursor
+			case 'B':
+				if bestSize == -1 || gap < bestSize {
+					best, bestSize = cursor, gap
+				}
+			case 'W':
+				if gap > bestSize {
+					best, bestSize = cursor, gap
+				}
+			}
+		}
+		if part.End > cursor {
+			cursor = part.End
+		}
+	}
+	return best
+}
+
 func countUsedPartitions(partitions [4]structures.Partition) int {
 	count := 0
 	for _, part := range partitions {
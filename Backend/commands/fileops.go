@@ -0,0 +1,282 @@
+package commands
+
+import (
+	stores "backend/stores"
+	structures "backend/structures"
+	utils "backend/utils"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Errores que permiten a quien llama (por ejemplo la API HTTP) distinguir el motivo de la falla
+var (
+	ErrNotAuthenticated = errors.New("no se ha iniciado sesión")
+	ErrPermissionDenied = errors.New("permiso denegado")
+	ErrNotFound         = errors.New("no existe")
+	ErrAlreadyExists    = errors.New("ya existe")
+	ErrInvalidPath      = errors.New("ruta inválida")
+)
+
+// CreateFolderAt crea una carpeta en la partición indicada con las mismas reglas que mkdir
+func CreateFolderAt(session *stores.AuthStore, partitionID string, dirPath string, allowParents bool) error {
+	sb, mountedPartition, diskPath, unlock, err := sessionPartition(session, partitionID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return createDirectory(session, dirPath, sb, diskPath, mountedPartition, allowParents)
+}
+
+// WriteFileAt crea el archivo filePath con el contenido indicado. Si ya existe solo lo
+// reemplaza cuando overwrite es true. Devuelve true si el archivo fue creado.
+func WriteFileAt(session *stores.AuthStore, partitionID string, filePath string, content []byte, overwrite bool) (bool, error) {
+	sb, mountedPartition, diskPath, unlock, err := sessionPartition(session, partitionID)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	parentIndex, parent, name, err := resolveParent(sb, diskPath, filePath)
+	if err != nil {
+		return false, err
+	}
+
+	childIndex, found, err := sb.FindChild(diskPath, parent, name)
+	if err != nil {
+		return false, err
+	}
+
+	if found {
+		if !overwrite {
+			return false, fmt.Errorf("%w: el archivo %s", ErrAlreadyExists, filePath)
+		}
+		inode, err := sb.ReadInode(diskPath, childIndex)
+		if err != nil {
+			return false, err
+		}
+		if inode.I_type[0] != '1' {
+			return false, fmt.Errorf("%w: %s es una carpeta", ErrInvalidPath, filePath)
+		}
+		if !utils.HasWritePermission(session, *inode) {
+			return false, fmt.Errorf("%w: no tiene permiso de escritura en %s", ErrPermissionDenied, filePath)
+		}
+		if err := sb.WriteFileContent(diskPath, childIndex, inode, content); err != nil {
+			// Los contadores del superbloque se guardan aunque la escritura falle
+			return false, errors.Join(err, sb.Serialize(diskPath, int64(mountedPartition.Start)))
+		}
+	} else {
+		if !utils.HasWritePermission(session, *parent) {
+			return false, fmt.Errorf("%w: no tiene permiso de escritura en la carpeta padre", ErrPermissionDenied)
+		}
+		info := session.Info()
+		uid, gid := int32(info.UserID), int32(info.GroupID)
+		if _, err := sb.CreateInode(diskPath, parentIndex, name, '1', uid, gid, "664", content); err != nil {
+			return false, errors.Join(err, sb.Serialize(diskPath, int64(mountedPartition.Start)))
+		}
+	}
+
+	if err := sb.Serialize(diskPath, int64(mountedPartition.Start)); err != nil {
+		return false, fmt.Errorf("error al serializar el superbloque: %w", err)
+	}
+
+	operation := "mkfile"
+	if found {
+		operation = "edit"
+	}
+	recordJournal(sb, diskPath, mountedPartition, operation, filePath, string(content))
+	return !found, nil
+}
+
+// RenameAt cambia el nombre de un archivo o carpeta sin moverlo de carpeta
+func RenameAt(session *stores.AuthStore, partitionID string, entryPath string, newName string) error {
+	sb, mountedPartition, diskPath, unlock, err := sessionPartition(session, partitionID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if newName == "" || newName == "." || newName == ".." || strings.Contains(newName, "/") {
+		return fmt.Errorf("%w: nombre '%s'", ErrInvalidPath, newName)
+	}
+	if len(newName) > structures.MaxNameLength {
+		return fmt.Errorf("%w: %s", ErrInvalidPath, structures.ErrNameTooLong)
+	}
+
+	parentIndex, parent, name, err := resolveParent(sb, diskPath, entryPath)
+	if err != nil {
+		return err
+	}
+	if isProtectedEntry(parentIndex, name) {
+		return fmt.Errorf("%w: no se puede renombrar %s", ErrPermissionDenied, entryPath)
+	}
+	if !utils.HasWritePermission(session, *parent) {
+		return fmt.Errorf("%w: no tiene permiso de escritura en la carpeta padre", ErrPermissionDenied)
+	}
+
+	childIndex, found, err := sb.FindChild(diskPath, parent, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, entryPath)
+	}
+	if _, exists, err := sb.FindChild(diskPath, parent, newName); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("%w: %s en la carpeta", ErrAlreadyExists, newName)
+	}
+
+	inode, err := sb.ReadInode(diskPath, childIndex)
+	if err != nil {
+		return err
+	}
+	if !utils.HasWritePermission(session, *inode) {
+		return fmt.Errorf("%w: no tiene permiso de escritura en %s", ErrPermissionDenied, entryPath)
+	}
+
+	if err := sb.RenameDirEntry(diskPath, parentIndex, name, newName); err != nil {
+		return err
+	}
+	if err := sb.Serialize(diskPath, int64(mountedPartition.Start)); err != nil {
+		return err
+	}
+	recordJournal(sb, diskPath, mountedPartition, "rename", entryPath, newName)
+	return nil
+}
+
+// RemoveAt elimina un archivo o una carpeta con todo su contenido. Se necesita permiso
+// de escritura sobre todo lo que se va a eliminar; si falta en algo no se elimina nada.
+func RemoveAt(session *stores.AuthStore, partitionID string, entryPath string) error {
+	sb, mountedPartition, diskPath, unlock, err := sessionPartition(session, partitionID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	parentIndex, parent, name, err := resolveParent(sb, diskPath, entryPath)
+	if err != nil {
+		return err
+	}
+	if isProtectedEntry(parentIndex, name) {
+		return fmt.Errorf("%w: no se puede eliminar %s", ErrPermissionDenied, entryPath)
+	}
+	if !utils.HasWritePermission(session, *parent) {
+		return fmt.Errorf("%w: no tiene permiso de escritura en la carpeta padre", ErrPermissionDenied)
+	}
+
+	childIndex, found, err := sb.FindChild(diskPath, parent, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, entryPath)
+	}
+
+	if err := checkWriteRecursive(session, sb, diskPath, childIndex, entryPath); err != nil {
+		return err
+	}
+
+	if err := sb.RemoveDirEntry(diskPath, parentIndex, name); err != nil {
+		return err
+	}
+	if err := sb.RemoveInode(diskPath, childIndex); err != nil {
+		// Devolver la entrada para que el inodo no quede ocupado sin nadie que lo apunte
+		return errors.Join(err,
+			sb.AddDirEntry(diskPath, parentIndex, name, childIndex),
+			sb.Serialize(diskPath, int64(mountedPartition.Start)))
+	}
+	if err := sb.Serialize(diskPath, int64(mountedPartition.Start)); err != nil {
+		return err
+	}
+	recordJournal(sb, diskPath, mountedPartition, "remove", entryPath, "")
+	return nil
+}
+
+// sessionPartition valida que la sesión esté abierta en partitionID, toma el candado de
+// escritura de su disco y devuelve su superbloque junto con la función que libera el candado
+func sessionPartition(session *stores.AuthStore, partitionID string) (*structures.SuperBlock, *structures.MountedPartition, string, func(), error) {
+	if !session.IsAuthenticated() {
+		return nil, nil, "", nil, ErrNotAuthenticated
+	}
+	if session.GetPartitionID() != partitionID {
+		return nil, nil, "", nil, fmt.Errorf("%w: la sesión activa pertenece a otra partición", ErrPermissionDenied)
+	}
+
+	unlock, err := stores.LockPartition(partitionID)
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+
+	sb, mountedPartition, diskPath, err := stores.GetMountedPartitionSuperblock(partitionID)
+	if err != nil {
+		unlock()
+		return nil, nil, "", nil, fmt.Errorf("%w: partición %s: %v", ErrNotFound, partitionID, err)
+	}
+	return sb, mountedPartition, diskPath, unlock, nil
+}
+
+// resolveParent busca la carpeta que contiene entryPath y devuelve su índice, su inodo y el nombre final
+func resolveParent(sb *structures.SuperBlock, diskPath string, entryPath string) (int32, *structures.Inode, string, error) {
+	cleanPath := path.Clean("/" + entryPath)
+	if cleanPath == "/" {
+		return -1, nil, "", fmt.Errorf("%w: %s", ErrInvalidPath, entryPath)
+	}
+
+	dir, name := path.Split(cleanPath)
+	if len(name) > structures.MaxNameLength {
+		return -1, nil, "", fmt.Errorf("%w: %s", ErrInvalidPath, structures.ErrNameTooLong)
+	}
+
+	parentIndex, err := structures.FindInodeByPath(diskPath, dir, *sb)
+	if err != nil {
+		return -1, nil, "", fmt.Errorf("%w: la carpeta %s", ErrNotFound, dir)
+	}
+	parent, err := sb.ReadInode(diskPath, parentIndex)
+	if err != nil {
+		return -1, nil, "", err
+	}
+	if parent.I_type[0] != '0' {
+		return -1, nil, "", fmt.Errorf("%w: %s no es una carpeta", ErrInvalidPath, dir)
+	}
+	return parentIndex, parent, name, nil
+}
+
+// recordJournal anota la operación en el journal de la partición (solo EXT3). La operación
+// ya quedó hecha, así que si no se puede anotar solo se avisa.
+func recordJournal(sb *structures.SuperBlock, diskPath string, partition *structures.MountedPartition, operation string, target string, content string) {
+	if err := sb.AppendJournal(diskPath, partition.Start, operation, target, content); err != nil {
+		fmt.Println("Advertencia: no se pudo registrar la operación en el journal:", err)
+	}
+}
+
+// isProtectedEntry indica si la entrada es /users.txt, que el sistema necesita para las sesiones
+func isProtectedEntry(parentIndex int32, name string) bool {
+	return parentIndex == 0 && name == "users.txt"
+}
+
+// checkWriteRecursive verifica el permiso de escritura sobre el inodo y todo su contenido
+func checkWriteRecursive(session *stores.AuthStore, sb *structures.SuperBlock, diskPath string, index int32, entryPath string) error {
+	inode, err := sb.ReadInode(diskPath, index)
+	if err != nil {
+		return err
+	}
+	if !utils.HasWritePermission(session, *inode) {
+		return fmt.Errorf("%w: no tiene permiso de escritura en %s", ErrPermissionDenied, entryPath)
+	}
+	if inode.I_type[0] != '0' {
+		return nil
+	}
+
+	children, err := sb.ListDirectory(diskPath, inode)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := checkWriteRecursive(session, sb, diskPath, child.Inode, path.Join(entryPath, child.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	help
+	help mkdisk
+*/
+
+// ParseHelp muestra la lista de comandos o la ayuda de un comando
+func ParseHelp(params Params) (Result, error) {
+	name := params.Value("command")
+	if name == "" {
+		return Result{Message: helpAll(), Data: ListCommands()}, nil
+	}
+
+	spec, ok := LookupCommand(name)
+	if !ok {
+		return Result{}, fmt.Errorf("comando desconocido: %s", name)
+	}
+	return Result{Message: helpCommand(spec), Data: spec}, nil
+}
+
+// helpAll lista los comandos con su descripción
+func helpAll() string {
+	var sb strings.Builder
+	sb.WriteString("========================== HELP ===============================\n")
+	sb.WriteString("Comandos disponibles:\n")
+	for _, spec := range ListCommands() {
+		sb.WriteString(fmt.Sprintf("-> %-8s: %s\n", spec.Name, spec.Description))
+	}
+	sb.WriteString("Use help <comando> para ver sus parámetros.\n")
+	sb.WriteString("=================================================================")
+	return sb.String()
+}
+
+// helpCommand muestra el uso, los parámetros y un ejemplo del comando
+func helpCommand(spec *CommandSpec) string {
+	var sb strings.Builder
+	sb.WriteString("========================== HELP ===============================\n")
+	sb.WriteString(fmt.Sprintf("%s: %s\n", spec.Name, spec.Description))
+	sb.WriteString(fmt.Sprintf("Uso: %s\n", spec.Usage))
+
+	if len(spec.Params) > 0 {
+		sb.WriteString("Parámetros:\n")
+		for _, ps := range spec.Params {
+			var notes []string
+			if ps.Required {
+				notes = append(notes, "obligatorio")
+			}
+			if ps.Default != "" {
+				notes = append(notes, "por defecto "+ps.Default)
+			}
+
+			line := fmt.Sprintf("-> %-14s %s", ps.usage(), ps.Description)
+			if len(notes) > 0 {
+				line += " (" + strings.Join(notes, ", ") + ")"
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	if spec.Example != "" {
+		sb.WriteString(fmt.Sprintf("Ejemplo: %s\n", spec.Example))
+	}
+	sb.WriteString("=================================================================")
+	return sb.String()
+}
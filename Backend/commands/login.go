@@ -2,11 +2,12 @@ package commands
 
 import (
 	stores "backend/stores"
+	structures "backend/structures"
+	utils "backend/utils"
 	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
+	"time"
 )
 
 // LOGIN estructura que representa el comando login con sus parámetros
@@ -20,164 +21,128 @@ type LOGIN struct {
 	login -user=root -pass=123 -id=062A3E2D
 */
 
-func ParseLogin(tokens []string) (string, error) {
-	cmd := &LOGIN{} // Crea una nueva instancia de LOGIN
-
-	// Unir tokens en una sola cadena y luego dividir por espacios, respetando las comillas
-	args := strings.Join(tokens, " ")
-	// Expresión regular para encontrar los parámetros del comando mkfs
-	re := regexp.MustCompile(`-user=[^\s]+|-pass=[^\s]+|-id=[^\s]+`)
-	// Encuentra todas las coincidencias de la expresión regular en la cadena de argumentos
-	matches := re.FindAllString(args, -1)
-
-
-
-
-
-	// Verificar que todos los tokens fueron reconocidos por la expresión regular
-	if len(matches) != len(tokens) {
-		// Identificar el parámetro inválido
-		for _, token := range tokens {
-			if !re.MatchString(token) {
-				return "", fmt.Errorf("parámetro inválido: %s", token)
-			}
-		}
-	}
-
-	// Itera sobre cada coincidencia encontrada
-	for _, match := range matches {
-		// Divide cada parte en clave y valor usando "=" como delimitador
-		kv := strings.SplitN(match, "=", 2)
-		if len(kv) != 2 {
-			return "", fmt.Errorf("formato de parámetro inválido: %s", match)
-		}
-		key, value := strings.ToLower(kv[0]), kv[1]
-
-		// Remove quotes from value if present
-		if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-			value = strings.Trim(value, "\"")
-		}
-
-		// Switch para manejar diferentes parámetros
-		switch key {
-		case "-user":
-			if value == "" {
-				return "", errors.New("el usuario no puede estar vacío")
-			}
-			cmd.user = value
-		case "-pass":
-			if value == "" {
-				return "", errors.New("la contraseña no puede estar vacía")
-			}
-			cmd.pass = value
-		case "-id":
-			// Verifica que el id no esté vacío
-			if value == "" {
-				return "", errors.New("el id no puede estar vacío")
-			}
-			cmd.id = value
-		default:
-			// Si el parámetro no es reconocido, devuelve un error
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	// Verifica que el parámetro -id haya sido proporcionado
-	if cmd.id == "" {
-		return "", errors.New("faltan parámetros requeridos: -id")
-	}
-
-	// Si no se proporcionó el tipo, se establece por defecto a "full"
-	if cmd.user == "" {
-		return "", errors.New("faltan parámetros requeridos: -user")
-	}
-
-	// Si no se proporcionó el tipo, se establece por defecto a "full"
-	if cmd.pass == "" {
-		return "", errors.New("faltan parámetros requeridos: -pass")
+func ParseLogin(params Params, session *stores.AuthStore) (Result, error) {
+	cmd := &LOGIN{
+		user: params.Value("user"),
+		pass: params.Value("pass"),
+		id:   params.Value("id"),
 	}
 
 	// Aquí se puede agregar la lógica para ejecutar el comando mkfs con los parámetros proporcionados
-	err := commandLogin(cmd)
+	err := commandLogin(cmd, session)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf(
+	return Result{Message: fmt.Sprintf(
 		"\n=============================LOGIN==============================\n"+
-		"LOGIN: Usuario: %s, Contraseña: %s, ID: %s\n"+
+		"LOGIN: Usuario: %s, ID: %s\n"+
 		"==================================================================",
-		cmd.user, cmd.pass, cmd.id), nil
+		cmd.user, cmd.id),
+		Data: SessionData{User: cmd.user, PartitionID: cmd.id},
+	}, nil
 	
 }
 
-func commandLogin(login *LOGIN) error {
-	if stores.Auth.IsAuthenticated() {
+// ErrAccountLocked indica que la cuenta está bloqueada por intentos fallidos de login
+var ErrAccountLocked = errors.New("la cuenta está bloqueada")
+
+// Login inicia sesión en la sesión indicada con las mismas validaciones del comando login
+func Login(session *stores.AuthStore, user string, pass string, id string) error {
+	return commandLogin(&LOGIN{user: user, pass: pass, id: id}, session)
+}
+
+func commandLogin(login *LOGIN, session *stores.AuthStore) error {
+	if session.IsAuthenticated() {
 		return fmt.Errorf("ya hay un usuario logueado, debe hacer logout primero")
 	}
 
-	partitionSuperblock, _, partitionPath, err := stores.GetMountedPartitionSuperblock(login.id)
+	// Escritura porque el login puede migrar la contraseña a hash en users.txt
+	unlock, err := stores.LockPartition(login.id)
 	if err != nil {
 		return fmt.Errorf("error al obtener la partición montada: %w", err)
 	}
+	defer unlock()
 
-	usersBlock, err := partitionSuperblock.GetUsersBlock(partitionPath)
+	partitionSuperblock, mountedPartition, partitionPath, err := stores.GetMountedPartitionSuperblock(login.id)
 	if err != nil {
-		return fmt.Errorf("error al obtener el bloque de usuarios: %w", err)
+		return fmt.Errorf("error al obtener la partición montada: %w", err)
+	}
+	mount, ok := stores.GetMountInfo(login.id)
+	if !ok {
+		return fmt.Errorf("error al obtener la partición montada: %s", login.id)
 	}
 
-	content := strings.Trim(string(usersBlock.B_content[:]), "\x00")
-	lines := strings.Split(content, "\n")
-
-	var foundUser bool
-	var uid, gid int
-	var userPassword string
-
-	for _, line := range lines {
-		fields := strings.Split(line, ",")
-		for i := range fields {
-			fields[i] = strings.TrimSpace(fields[i])
+	content, err := partitionSuperblock.ReadUsersFile(partitionPath)
+	if err != nil {
+		return fmt.Errorf("error al leer users.txt: %w", err)
+	}
+	users := utils.ParseUsersFile(content)
+
+	// Formato esperado: UID, U, grupo, usuario, contraseña
+	var user *utils.UserRecord
+	for i := range users.Users {
+		if users.Users[i].UID != 0 && strings.EqualFold(users.Users[i].Username, login.user) {
+			user = &users.Users[i]
+			break
 		}
+	}
+	if user == nil {
+		return fmt.Errorf("el usuario %s no existe", login.user)
+	}
 
-		if len(fields) == 5 && fields[1] == "U" {
-			// Formato esperado: UID, U, grupo, usuario, contraseña
-			if strings.EqualFold(fields[3], login.user) {
-				foundUser = true
-				userPassword = fields[4]
-
-				// Convertir UID (fields[0]) y buscar GID por grupo
-				uidParsed, err := strconv.Atoi(fields[0])
-				if err != nil {
-					return fmt.Errorf("error al convertir UID del usuario")
-				}
-				uid = uidParsed
-
-				// Buscar GID (busca grupo por nombre)
-				for _, gline := range lines {
-					gfields := strings.Split(gline, ",")
-					if len(gfields) == 3 && gfields[1] == "G" && strings.EqualFold(gfields[2], fields[2]) {
-						gidParsed, err := strconv.Atoi(gfields[0])
-						if err != nil {
-							return fmt.Errorf("error al convertir GID del grupo")
-						}
-						gid = gidParsed
-						break
-					}
-				}
-				break
-			}
-		}
+	if err := checkPassword(mount, user, login.pass); err != nil {
+		return err
 	}
 
-	if !foundUser {
-		return fmt.Errorf("el usuario %s no existe", login.user)
+	gid := users.GroupID(user.Group)
+	if gid == -1 {
+		return fmt.Errorf("el grupo %s del usuario no existe", user.Group)
 	}
 
-	if !strings.EqualFold(userPassword, login.pass) {
-		return fmt.Errorf("la contraseña no coincide")
+	// Las contraseñas que aún están en texto plano se reemplazan por su hash
+	if !utils.IsPasswordHash(user.Password) {
+		if err := savePassword(partitionSuperblock, partitionPath, mountedPartition.Start, content, user.Username, login.pass); err != nil {
+			return fmt.Errorf("error al actualizar la contraseña en users.txt: %w", err)
+		}
 	}
 
 	// Guardar sesión
-	stores.Auth.Login(login.user, login.pass, login.id, uid, gid)
+	session.Login(user.Username, login.id, user.UID, gid)
+	return nil
+}
+
+// checkPassword verifica la contraseña de un usuario de la partición aplicando el bloqueo
+// por intentos fallidos; lo usan login y passwd -old
+func checkPassword(mount stores.MountInfo, user *utils.UserRecord, password string) error {
+	// Una cuenta bloqueada no se valida aunque la contraseña sea correcta
+	if remaining := stores.LoginLockedFor(mount, user.Username); remaining > 0 {
+		return fmt.Errorf("%w: intente de nuevo en %s", ErrAccountLocked, remaining.Round(time.Second))
+	}
+
+	if !utils.VerifyPassword(user.Password, password) {
+		lockout, left := stores.RegisterLoginFailure(mount, user.Username)
+		if lockout > 0 {
+			return fmt.Errorf("%w: demasiados intentos fallidos, la cuenta %s queda bloqueada por %s", ErrAccountLocked, user.Username, lockout)
+		}
+		return fmt.Errorf("la contraseña no coincide, quedan %d intentos antes de bloquear la cuenta", left)
+	}
+	stores.ResetLoginFailures(mount, user.Username)
 	return nil
 }
+
+// savePassword guarda en users.txt el hash de la contraseña del usuario
+func savePassword(sb *structures.SuperBlock, path string, start int32, content string, username string, password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	newContent, err := utils.SetUserPassword(content, username, hash)
+	if err != nil {
+		return err
+	}
+	if err := sb.WriteUsersFile(path, newContent); err != nil {
+		return err
+	}
+	return sb.Serialize(path, int64(start))
+}
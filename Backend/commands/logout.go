@@ -6,19 +6,18 @@ import (
 	"fmt"
 )
 
-func ParseLogout(tokens []string) (string, error) {
-	// Validar que no se hayan enviado parámetros
-	if len(tokens) > 0 {
-		return "", errors.New("el comando logout no acepta parámetros")
-	}
-
+func ParseLogout(params Params, session *stores.AuthStore) (Result, error) {
 	// Verificar si hay una sesión activa
-	if !stores.Auth.IsAuthenticated() {
-		return "", errors.New("no hay una sesión activa para cerrar")
+	if !session.IsAuthenticated() {
+		return Result{}, errors.New("no hay una sesión activa para cerrar")
 	}
 
 	// Cerrar sesión
-	stores.Auth.Logout()
+	username, partitionID := session.GetCurrentUser()
+	session.Logout()
 
-	return fmt.Sprintf("Sesión cerrada exitosamente."), nil
+	return Result{
+		Message: fmt.Sprintf("Sesión cerrada exitosamente."),
+		Data:    SessionData{User: username, PartitionID: partitionID},
+	}, nil
 }
@@ -6,8 +6,6 @@ import (
 	utils "backend/utils"
 	"errors"
 	"fmt"
-	"regexp"
-	"strings"
 	
 )
 
@@ -16,66 +14,37 @@ type MKDIR struct {
 	p    bool
 }
 
-func ParseMkdir(tokens []string) (string, error) {
-	cmd := &MKDIR{}
+func ParseMkdir(params Params, session *stores.AuthStore) (Result, error) {
+	cmd := &MKDIR{path: params.Value("path"), p: params.Has("p")}
 
-	args := strings.Join(tokens, " ")
-	re := regexp.MustCompile(`-path="[^"]+"|-path=[^\s]+|-p(\s|$)`)
-	matches := re.FindAllString(args, -1)
-
-	if len(matches) != len(tokens) {
-		for _, token := range tokens {
-			if !re.MatchString(token) {
-				return "", fmt.Errorf("parámetro inválido: %s", token)
-			}
-		}
-	}
-
-	for _, match := range matches {
-		kv := strings.SplitN(match, "=", 2)
-		key := strings.ToLower(kv[0])
-
-		switch key {
-		case "-path":
-			if len(kv) != 2 {
-				return "", fmt.Errorf("formato de parámetro inválido: %s", match)
-			}
-			value := strings.Trim(kv[1], "\"")
-			cmd.path = value
-		case "-p":
-			if len(kv) > 1 {
-				return "", errors.New("el parámetro -p no debe llevar valor")
-			}
-			cmd.p = true
-		default:
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	if cmd.path == "" {
-		return "", errors.New("faltan parámetros requeridos: -path")
-	}
-
-	err := commandMkdir(cmd)
+	err := commandMkdir(cmd, session)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf("MKDIR: Directorio %s creado correctamente.", cmd.path), nil
+	return Result{Message: fmt.Sprintf("MKDIR: Directorio %s creado correctamente.", cmd.path),
+		Data: FolderData{Path: cmd.path, Parents: cmd.p},
+	}, nil
 }
 
-func commandMkdir(mkdir *MKDIR) error {
-	if !stores.Auth.IsAuthenticated() {
+func commandMkdir(mkdir *MKDIR, session *stores.AuthStore) error {
+	if !session.IsAuthenticated() {
 		return errors.New("no se ha iniciado sesión en ninguna partición")
 	}
 
-	partitionID := stores.Auth.GetPartitionID()
+	partitionID := session.GetPartitionID()
+	unlock, err := stores.LockPartition(partitionID)
+	if err != nil {
+		return fmt.Errorf("error al obtener la partición montada: %w", err)
+	}
+	defer unlock()
+
 	sb, mountedPartition, partitionPath, err := stores.GetMountedPartitionSuperblock(partitionID)
 	if err != nil {
 		return fmt.Errorf("error al obtener la partición montada: %w", err)
 	}
 
-	err = createDirectory(mkdir.path, sb, partitionPath, mountedPartition, mkdir.p)
+	err = createDirectory(session, mkdir.path, sb, partitionPath, mountedPartition, mkdir.p)
 	if err != nil {
 		return fmt.Errorf("error al crear el directorio: %w", err)
 	}
@@ -83,34 +52,67 @@ func commandMkdir(mkdir *MKDIR) error {
 	return nil
 }
 
-func createDirectory(dirPath string, sb *structures.SuperBlock, partitionPath string, mountedPartition *structures.Partition, allowParents bool) error {
+func createDirectory(session *stores.AuthStore, dirPath string, sb *structures.SuperBlock, partitionPath string, mountedPartition *structures.MountedPartition, allowParents bool) error {
 	parentDirs, destDir := utils.GetParentDirectories(dirPath)
+	if destDir == "" || destDir == "." || destDir == ".." {
+		return fmt.Errorf("%w: %s", ErrInvalidPath, dirPath)
+	}
+	if len(destDir) > structures.MaxNameLength {
+		return fmt.Errorf("%w: %s", ErrInvalidPath, structures.ErrNameTooLong)
+	}
 
-	if !allowParents {
-		exists := sb.DirectoriesExist(partitionPath, parentDirs)
-		if !exists {
-			return fmt.Errorf("las carpetas padres no existen y no se especificó -p")
+	// Buscar la carpeta existente más profunda, que es la que recibe la nueva entrada
+	ancestorIndex := int32(0)
+	missing := false
+	for _, dirName := range parentDirs {
+		ancestor, err := sb.ReadInode(partitionPath, ancestorIndex)
+		if err != nil {
+			return err
+		}
+		childIndex, found, err := sb.FindChild(partitionPath, ancestor, dirName)
+		if err != nil {
+			return err
 		}
+		if !found {
+			missing = true
+			break
+		}
+		ancestorIndex = childIndex
+	}
+
+	if missing && !allowParents {
+		return fmt.Errorf("%w: las carpetas padres no existen y no se especificó -p", ErrNotFound)
 	}
 
-	if len(parentDirs) > 0 {
-		parentInode := sb.FindInodeByPath(partitionPath, "/"+strings.Join(parentDirs, "/"))
-		if parentInode != nil && !utils.HasWritePermission(*parentInode) {
-			return fmt.Errorf("no tiene permiso de escritura en la carpeta padre")
+	ancestor, err := sb.ReadInode(partitionPath, ancestorIndex)
+	if err != nil {
+		return err
+	}
+	if ancestor.I_type[0] != '0' {
+		return fmt.Errorf("%w: la ruta padre no es una carpeta", ErrInvalidPath)
+	}
+	if !missing {
+		if _, found, err := sb.FindChild(partitionPath, ancestor, destDir); err != nil {
+			return err
+		} else if found {
+			return fmt.Errorf("%w: %s", ErrAlreadyExists, dirPath)
 		}
 	}
+	if !utils.HasWritePermission(session, *ancestor) {
+		return fmt.Errorf("%w: no tiene permiso de escritura en la carpeta padre", ErrPermissionDenied)
+	}
 
-	err := sb.CreateFolder(partitionPath, parentDirs, destDir, allowParents)
+	info := session.Info()
+	err = sb.CreateFolder(partitionPath, parentDirs, destDir, allowParents, int32(info.UserID), int32(info.GroupID))
 	if err != nil {
 		return fmt.Errorf("error al crear el directorio: %w", err)
 	}
 
-	err = sb.Serialize(partitionPath, int64(mountedPartition.Part_start))
+	err = sb.Serialize(partitionPath, int64(mountedPartition.Start))
 	if err != nil {
 		return fmt.Errorf("error al serializar el superbloque: %w", err)
 	}
 
+	recordJournal(sb, partitionPath, mountedPartition, "mkdir", dirPath, "")
 	return nil
 }
-
-
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	stores "backend/stores"
 	structures "backend/structures"
 	utils "backend/utils"
 	"errors"        // Paquete para manejar errores y crear nuevos errores con mensajes personalizados
@@ -10,17 +11,17 @@ import (
 	"path/filepath" // Paquete para trabajar con rutas de archivos y directorios
 	//"regexp"        // Paquete para trabajar con expresiones regulares, útil para encontrar y manipular patrones en cadenas
 	"strconv"       // Paquete para convertir cadenas a otros tipos de datos, como enteros
-	"strings"       // Paquete para manipular cadenas, como unir, dividir, y modificar contenido de cadenas
 	"time"
 	
 )
 
 // MKDISK estructura que representa el comando mkdisk con sus parámetros
 type MKDISK struct {
-	size int    // Tamaño del disco
-	unit string // Unidad de medida del tamaño (K o M)
-	fit  string // Tipo de ajuste (BF, FF, WF)
-	path string // Ruta del archivo del disco
+	size  int    // Tamaño del disco
+	unit  string // Unidad de medida del tamaño (K o M)
+	fit   string // Tipo de ajuste (BF, FF, WF)
+	path  string // Ruta del archivo del disco
+	table string // Tabla de particiones (MBR o GPT)
 }
 
 /*
@@ -28,95 +29,60 @@ type MKDISK struct {
    mkdisk -size=3000 -path=/home/user/Disco1.mia
    mkdisk -size=5 -unit=M -fit=WF -path="/home/keviin/University/PRACTICAS/MIA_LAB_S2_2024/CLASE03/disks/Disco1.mia"
    mkdisk -size=10 -path="/home/mis discos/Disco4.mia"
+   mkdisk -size=20 -table=gpt -path=/home/user/Disco5.mia
 */
 
-func ParseMkdisk(tokens []string) (string, error) {
-	cmd := &MKDISK{}
-
-	// Iterar sobre todos los tokens
-	for _, token := range tokens {
-		lower := strings.ToLower(token)
-
-		if strings.HasPrefix(lower, "-size=") {
-			value := strings.SplitN(token, "=", 2)[1]
-			size, err := strconv.Atoi(value)
-			if err != nil || size <= 0 {
-				return "", errors.New("el tamaño debe ser un número entero positivo")
-			}
-			cmd.size = size
-
-		} else if strings.HasPrefix(lower, "-unit=") {
-			value := strings.SplitN(token, "=", 2)[1]
-			value = strings.ToUpper(strings.Trim(value, "\""))
-			if value != "K" && value != "M" {
-				return "", errors.New("la unidad debe ser K o M")
-			}
-			cmd.unit = value
-
-		} else if strings.HasPrefix(lower, "-fit=") {
-			value := strings.SplitN(token, "=", 2)[1]
-			value = strings.ToUpper(strings.Trim(value, "\""))
-			if value != "FF" && value != "BF" && value != "WF" {
-				return "", errors.New("el ajuste debe ser FF, BF o WF")
-			}
-			cmd.fit = value
-
-		} else if strings.HasPrefix(lower, "-path=") {
-			value := strings.SplitN(token, "=", 2)[1]
-			value = strings.Trim(value, "\"")
-			if value == "" {
-				return "", errors.New("el path no puede estar vacío")
-			}
-			cmd.path = value
-
-		} else {
-			// ❌ Parámetro no reconocido
-			return "", fmt.Errorf("parámetro no reconocido: %s", token)
-		}
-	}
-
-	// Validaciones obligatorias
-	if cmd.size == 0 {
-		return "", errors.New("faltan parámetros requeridos: -size")
-	}
-	if cmd.path == "" {
-		return "", errors.New("faltan parámetros requeridos: -path")
-	}
-
-	// Defaults
-	if cmd.unit == "" {
-		cmd.unit = "M"
-	}
-	if cmd.fit == "" {
-		cmd.fit = "FF"
+func ParseMkdisk(params Params) (Result, error) {
+	// Los parámetros ya vienen validados y con sus valores por defecto desde el registro
+	size, _ := strconv.Atoi(params.Value("size"))
+	cmd := &MKDISK{
+		size:  size,
+		unit:  params.Value("unit"),
+		fit:   params.Value("fit"),
+		path:  filepath.Clean(params.Value("path")),
+		table: params.Value("table"),
 	}
 
 	// Ejecutar creación del disco
 	err := commandMkdisk(cmd)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
 	diskName := filepath.Base(cmd.path)
 
 	// Éxito
-	return fmt.Sprintf(
+	return Result{Message: fmt.Sprintf(
 		"========================== MKDISK ===============================\n"+
 			"MKDISK: Disco creado exitosamente\n"+
 			"-> Path: %s\n"+
 			"-> Nombre: %s\n"+
 			"-> Tamaño: %d%s\n"+
 			"-> Fit: %s\n"+
+			"-> Tabla: %s\n"+
 			"=================================================================\n",
-		cmd.path, diskName, cmd.size, cmd.unit, cmd.fit), nil
+		cmd.path, diskName, cmd.size, cmd.unit, cmd.fit, cmd.table),
+		Data: DiskData{Path: cmd.path, Name: diskName, Size: cmd.size, Unit: cmd.unit, Fit: cmd.fit, Table: cmd.table},
+	}, nil
 }
 
 
 func commandMkdisk(mkdisk *MKDISK) error {
+	// Nadie más puede usar el archivo del disco mientras se crea
+	unlock := stores.LockDisk(mkdisk.path)
+	defer unlock()
+
 	// Convertir el tamaño a bytes
 	sizeBytes, err := utils.ConvertToBytes(mkdisk.size, mkdisk.unit)
 	if err != nil {
-		return fmt.Errorf("Error creando disco: %v", err)
+		return fmt.Errorf("error creando disco: %v", err)
+	}
+
+	// Un disco GPT debe tener espacio para el encabezado y la tabla antes de crearlo
+	if mkdisk.table == "GPT" {
+		if _, _, err := structures.NewGPT(int32(sizeBytes)); err != nil {
+			return err
+		}
 	}
 
 	// Crear el disco con el tamaño proporcionado
@@ -133,6 +99,12 @@ func commandMkdisk(mkdisk *MKDISK) error {
 		return err
 	}
 
+	// Registrar el disco en el catálogo
+	stores.RegisterDisk(mkdisk.path)
+	if err := stores.SaveState(); err != nil {
+		fmt.Println("Advertencia: no se pudo guardar el catálogo de discos:", err)
+	}
+
 	return nil
 }
 
@@ -197,6 +169,11 @@ func createMBR(mkdisk *MKDISK, sizeBytes int) error {
 		},
 	}
 
+	// En un disco GPT el MBR solo protege el disco y la tabla real va después
+	if mkdisk.table == "GPT" {
+		mbr.Mbr_partitions[0] = structures.NewProtectiveMBRPartition(int32(sizeBytes))
+	}
+
 	/* SOLO PARA VERIFICACIÓN */
 	// Imprimir MBR
 	fmt.Println("\nMBR creado:")
@@ -208,5 +185,15 @@ func createMBR(mkdisk *MKDISK, sizeBytes int) error {
 		fmt.Println("Error:", err)
 	}
 
+	if mkdisk.table == "GPT" {
+		header, entries, err := structures.NewGPT(int32(sizeBytes))
+		if err != nil {
+			return err
+		}
+		if err := structures.WriteGPT(mkdisk.path, header, entries); err != nil {
+			return fmt.Errorf("error escribiendo la tabla GPT: %v", err)
+		}
+	}
+
 	return nil
 }
\ No newline at end of file
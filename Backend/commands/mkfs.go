@@ -4,10 +4,8 @@ import (
 	stores "backend/stores"
 	structures "backend/structures"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"math"
-	"regexp"
 	"strings"
 	"time"
 )
@@ -16,81 +14,42 @@ import (
 type MKFS struct {
 	id  string // ID del disco
 	typ string // Tipo de formato (full)
+	fs  int32  // Sistema de archivos: 2 (EXT2) o 3 (EXT3)
 }
 
 /*
    mkfs -id=vd1 -type=full
-   mkfs -id=vd2
+   mkfs -id=vd2 -fs=3fs
 */
 
-func ParseMkfs(tokens []string) (string, error) {
-	cmd := &MKFS{} // Crea una nueva instancia de MKFS
-
-	// Unir tokens en una sola cadena y luego dividir por espacios, respetando las comillas
-	args := strings.Join(tokens, " ")
-	// Expresión regular para encontrar los parámetros del comando mkfs
-	re := regexp.MustCompile(`-id=[^\s]+|-type=[^\s]+`)
-	// Encuentra todas las coincidencias de la expresión regular en la cadena de argumentos
-	matches := re.FindAllString(args, -1)
-
-	// Itera sobre cada coincidencia encontrada
-	for _, match := range matches {
-		// Divide cada parte en clave y valor usando "=" como delimitador
-		kv := strings.SplitN(match, "=", 2)
-		if len(kv) != 2 {
-			return "", fmt.Errorf("formato de parámetro inválido: %s", match)
-		}
-		key, value := strings.ToLower(kv[0]), kv[1]
-
-		// Remove quotes from value if present
-		if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-			value = strings.Trim(value, "\"")
-		}
-
-		// Switch para manejar diferentes parámetros
-		switch key {
-		case "-id":
-			// Verifica que el id no esté vacío
-			if value == "" {
-				return "", errors.New("el id no puede estar vacío")
-			}
-			cmd.id = value
-		case "-type":
-			// Verifica que el tipo sea "full"
-			if value != "full" {
-				return "", errors.New("el tipo debe ser full")
-			}
-			cmd.typ = value
-		default:
-			// Si el parámetro no es reconocido, devuelve un error
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	// Verifica que el parámetro -id haya sido proporcionado
-	if cmd.id == "" {
-		return "", errors.New("faltan parámetros requeridos: -id")
-	}
-
-	// Si no se proporcionó el tipo, se establece por defecto a "full"
-	if cmd.typ == "" {
-		cmd.typ = "full"
+func ParseMkfs(params Params) (Result, error) {
+	cmd := &MKFS{id: params.Value("id"), typ: params.Value("type"), fs: 2}
+	if strings.EqualFold(params.Value("fs"), "3fs") {
+		cmd.fs = 3
 	}
 
 	// Aquí se puede agregar la lógica para ejecutar el comando mkfs con los parámetros proporcionados
 	err := commandMkfs(cmd)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf("MKFS: Sistema de archivos creado exitosamente\n"+
+	return Result{Message: fmt.Sprintf("MKFS: Sistema de archivos creado exitosamente\n"+
 		"-> ID: %s\n"+
 		"-> Tipo: %s\n"+
-		"-> Sistema de archivos: EXT2",
-		cmd.id, cmd.typ), nil
+		"-> Sistema de archivos: EXT%d",
+		cmd.id, cmd.typ, cmd.fs),
+		Data: FormatData{ID: cmd.id, Type: cmd.typ, Filesystem: fmt.Sprintf("EXT%d", cmd.fs)},
+	}, nil
 }
 
 func commandMkfs(mkfs *MKFS) error {
+	unlock, err := stores.LockPartition(mkfs.id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Obtener la partición montada
 	mountedPartition, partitionPath, err := stores.GetMountedPartition(mkfs.id)
 	if err != nil {
@@ -99,10 +58,10 @@ func commandMkfs(mkfs *MKFS) error {
 
 	// Verificar la partición montada
 	fmt.Println("\nPatición montada:")
-	mountedPartition.PrintPartition()
+	mountedPartition.Print()
 
 	// Calcular el valor de n
-	n := calculateN(mountedPartition)
+	n := calculateN(mountedPartition, mkfs.fs)
 
 	if n <= 0 {
 		return fmt.Errorf("no se puede formatear la partición: espacio insuficiente o inválido (n=%d)", n)
@@ -111,11 +70,8 @@ func commandMkfs(mkfs *MKFS) error {
 	// Verificar el valor de n
 	fmt.Println("\nValor de n:", n)
 
-	// Verificar el valor de n
-	fmt.Println("\nValor de n:", n)
-
 	// Inicializar un nuevo superbloque
-	superBlock := createSuperBlock(mountedPartition, n)
+	superBlock := createSuperBlock(mountedPartition, n, mkfs.fs)
 
 	// Verificar el superbloque
 	fmt.Println("\nSuperBlock:")
@@ -127,18 +83,33 @@ func commandMkfs(mkfs *MKFS) error {
 		return err
 	}
 
+	// En EXT3 el journal ocupa el espacio entre el superbloque y el bitmap de inodos
+	if mkfs.fs == 3 {
+		if err := superBlock.CreateJournal(partitionPath, mountedPartition.Start); err != nil {
+			return err
+		}
+	}
+
 	// Crear archivo users.txt
 	err = superBlock.CreateUsersFile(partitionPath)
 	if err != nil {
 		return err
 	}
 
+	// Registrar en el journal la carpeta raíz y users.txt
+	if err := superBlock.AppendJournal(partitionPath, mountedPartition.Start, "mkdir", "/", ""); err != nil {
+		return err
+	}
+	if err := superBlock.AppendJournal(partitionPath, mountedPartition.Start, "mkfile", "/users.txt", ""); err != nil {
+		return err
+	}
+
 	// Verificar superbloque actualizado
 	fmt.Println("\nSuperBlock actualizado:")
 	superBlock.Print()
 
 	// Serializar el superbloque
-	err = superBlock.Serialize(partitionPath, int64(mountedPartition.Part_start))
+	err = superBlock.Serialize(partitionPath, int64(mountedPartition.Start))
 	if err != nil {
 		return err
 	}
@@ -146,29 +117,38 @@ func commandMkfs(mkfs *MKFS) error {
 	return nil
 }
 
-func calculateN(partition *structures.Partition) int32 {
+func calculateN(partition *structures.MountedPartition, fs int32) int32 {
 	/*
 		numerador = (partition_montada.size - sizeof(Structs::Superblock)
 		denominador base = (4 + sizeof(Structs::Inodes) + 3 * sizeof(Structs::Fileblock))
+		en EXT3 el denominador suma sizeof(Structs::Journal): una entrada por inodo
 		n = floor(numerador / denominador)
 	*/
 
-	if partition.Part_size <= 1024 {
+	if partition.Size <= 1024 {
 		return 0 // tamaño demasiado pequeño para formatear
 	}
 	
 
-	numerator := int(partition.Part_size) - binary.Size(structures.SuperBlock{})
+	numerator := int(partition.Size) - binary.Size(structures.SuperBlock{})
 	denominator := 4 + binary.Size(structures.Inode{}) + 3*binary.Size(structures.FileBlock{}) // No importa que bloque poner, ya que todos tienen el mismo tamaño
+	if fs == 3 {
+		denominator += binary.Size(structures.Journal{})
+	}
 	n := math.Floor(float64(numerator) / float64(denominator))
 
 	return int32(n)
 }
 
-func createSuperBlock(partition *structures.Partition, n int32) *structures.SuperBlock {
+func createSuperBlock(partition *structures.MountedPartition, n int32, fs int32) *structures.SuperBlock {
 	// Calcular punteros de las estructuras
+	// Journal (solo EXT3): n entradas después del superbloque
+	journal_size := int32(0)
+	if fs == 3 {
+		journal_size = n * int32(binary.Size(structures.Journal{}))
+	}
 	// Bitmaps
-	bm_inode_start := partition.Part_start + int32(binary.Size(structures.SuperBlock{}))
+	bm_inode_start := partition.Start + int32(binary.Size(structures.SuperBlock{})) + journal_size
 	bm_block_start := bm_inode_start + n // n indica la cantidad de inodos, solo la cantidad para ser representada en un bitmap
 	// Inodos
 	inode_start := bm_block_start + (3 * n) // 3*n indica la cantidad de bloques, se multiplica por 3 porque se tienen 3 tipos de bloques
@@ -177,7 +157,7 @@ func createSuperBlock(partition *structures.Partition, n int32) *structures.Supe
 
 	// Crear un nuevo superbloque
 	superBlock := &structures.SuperBlock{
-		S_filesystem_type:   2,
+		S_filesystem_type:   fs,
 		S_inodes_count:      n,        // ✅ CORREGIDO
 		S_blocks_count:      n * 3, 
 		S_free_inodes_count: int32(n),
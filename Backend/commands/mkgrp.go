@@ -2,10 +2,8 @@ package commands
 
 import (
 	"backend/stores"
-	"backend/structures"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -15,62 +13,48 @@ type MKGRP struct {
 }
 
 // ParseMkgrp analiza los parámetros del comando mkgrp
-func ParseMkgrp(tokens []string) (string, error) {
-	cmd := &MKGRP{}
+func ParseMkgrp(params Params, session *stores.AuthStore) (Result, error) {
+	cmd := &MKGRP{name: params.Value("name")}
 
-	// Extraer parámetros con regex
-	args := strings.Join(tokens, " ")
-	re := regexp.MustCompile(`-name="[^"]+"|-name=[^\s]+`)
-	matches := re.FindAllString(args, -1)
-
-	for _, match := range matches {
-		kv := strings.SplitN(match, "=", 2)
-		key := strings.ToLower(kv[0])
-		value := strings.Trim(kv[1], "\"")
-
-		if key == "-name" {
-			cmd.name = value
-		} else {
-			return "", fmt.Errorf("parámetro no reconocido: %s", key)
-		}
-	}
-
-	if cmd.name == "" {
-		return "", errors.New("el parámetro -name es obligatorio")
-	}
-
-	err := commandMkgrp(cmd)
+	err := commandMkgrp(cmd, session)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf("Grupo '%s' creado exitosamente.", cmd.name), nil
+	return Result{Message: fmt.Sprintf("Grupo '%s' creado exitosamente.", cmd.name),
+		Data: GroupData{Name: cmd.name},
+	}, nil
 }
 
 // commandMkgrp ejecuta la lógica del comando
-func commandMkgrp(cmd *MKGRP) error {
+func commandMkgrp(cmd *MKGRP, session *stores.AuthStore) error {
 	// Verificar sesión
-	if !stores.Auth.IsAuthenticated() {
+	info := session.Info()
+	if !info.IsLoggedIn {
 		return errors.New("debe iniciar sesión para ejecutar este comando")
 	}
-	if stores.Auth.Username != "root" {
+	if info.Username != "root" {
 		return errors.New("solo el usuario root puede crear grupos")
 	}
 
-	// Obtener el superbloque y ruta
-	sb, _, path, err := stores.GetMountedPartitionSuperblock(stores.Auth.PartitionID)
+	unlock, err := stores.LockPartition(info.PartitionID)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	// Leer el bloque de users.txt
-	usersBlock, err := sb.GetUsersBlock(path)
+	// Obtener el superbloque y ruta
+	sb, mountedPartition, path, err := stores.GetMountedPartitionSuperblock(info.PartitionID)
 	if err != nil {
 		return err
 	}
 
-	// Leer contenido actual
-	content := strings.Trim(string(usersBlock.B_content[:]), "\x00")
+	// Leer users.txt completo
+	content, err := sb.ReadUsersFile(path)
+	if err != nil {
+		return err
+	}
+	content = strings.TrimRight(strings.Trim(content, "\x00"), "\n")
 	lines := strings.Split(content, "\n")
 
 	// Verificar si el grupo ya existe
@@ -99,34 +83,12 @@ func commandMkgrp(cmd *MKGRP) error {
 	// Agregar nueva línea al contenido
 	newLine := fmt.Sprintf("%d,G,%s", newID, cmd.name)
 	lines = append(lines, newLine)
-	newContent := strings.Join(lines, "\n")
-
-	// Copiar nuevo contenido al bloque
-	var newBlock [64]byte
-	copy(newBlock[:], []byte(newContent))
-	copy(usersBlock.B_content[:], newBlock[:])
-
-	// Obtener inodo de users.txt (siempre es inodo 1)
-	inode := &structures.Inode{}
-	err = inode.Deserialize(path, int64(sb.S_inode_start+sb.S_inode_size)) // inodo 1
-	if err != nil {
-		return err
-	}
-
-	blockIndex := inode.I_block[0]
-	if blockIndex == -1 {
-		return fmt.Errorf("el archivo users.txt no tiene bloque asignado")
-	}
+	newContent := strings.Join(lines, "\n") + "\n"
 
 	// Guardar el nuevo contenido en el disco
-	err = usersBlock.Serialize(
-		path,
-		int64(sb.S_block_start) + int64(blockIndex)*int64(sb.S_block_size),
-	)
-	
-	if err != nil {
+	if err := sb.WriteUsersFile(path, newContent); err != nil {
 		return err
 	}
 
-	return nil
+	return sb.Serialize(path, int64(mountedPartition.Start))
 }
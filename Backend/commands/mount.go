@@ -6,8 +6,10 @@ import (
 	utils "backend/utils"
 	"errors"
 	"fmt"
-	"regexp"
-	"strings"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 )
 
 type MOUNT struct {
@@ -15,115 +17,142 @@ type MOUNT struct {
 	name string
 }
 
-func ParseMount(tokens []string) (string, error) {
-	cmd := &MOUNT{}
-
-	args := strings.Join(tokens, " ")
-	re := regexp.MustCompile(`-path="[^"]+"|-path=[^\s]+|-name="[^"]+"|-name=[^\s]+`)
-	matches := re.FindAllString(args, -1)
-
-	for _, match := range matches {
-		kv := strings.SplitN(match, "=", 2)
-		if len(kv) != 2 {
-			return "", fmt.Errorf("formato de parámetro inválido: %s", match)
-		}
-		key, value := strings.ToLower(kv[0]), kv[1]
-		value = strings.Trim(value, "\"")
-
-		switch key {
-		case "-path":
-			if value == "" {
-				return "", errors.New("el path no puede estar vacío")
-			}
-			cmd.path = value
-		case "-name":
-			if value == "" {
-				return "", errors.New("el nombre no puede estar vacío")
-			}
-			cmd.name = value
-		default:
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	if cmd.path == "" || cmd.name == "" {
-		return "", errors.New("faltan parámetros requeridos: -path y/o -name")
-	}
+func ParseMount(params Params) (Result, error) {
+	cmd := &MOUNT{path: filepath.Clean(params.Value("path")), name: params.Value("name")}
 
 	idPartition, err := commandMount(cmd)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf(
+	return Result{Message: fmt.Sprintf(
 		"========================== MOUNT ===============================\n"+
 		 "MOUNT: Partición Montada\n"+
 			"-> Path    : %s\n" +
 			"-> Nombre  : %s\n" +
 			"-> ID      : %s\n" +
 		"=================================================================\n",
-		cmd.path, cmd.name, idPartition), nil
+		cmd.path, cmd.name, idPartition),
+		Data: MountData{ID: idPartition, Path: cmd.path, Name: cmd.name},
+	}, nil
 	
 }
 
 func commandMount(mount *MOUNT) (string, error) {
+	unlock := stores.LockDisk(mount.path)
+	defer unlock()
+
 	var mbr structures.MBR
 	if err := mbr.Deserialize(mount.path); err != nil {
 		return "", fmt.Errorf("error deserializando el MBR: %w", err)
 	}
 
-	// Buscar partición primaria
-	partition, _ := mbr.GetPartitionByName(mount.name)
-
-
-	if partition != nil && partition.Part_type[0] == 'E' {
-		return "", errors.New("no se puede montar una partición extendida directamente")
-	}
-
+	isGPT := mbr.IsGPT()
 	isLogical := false
+	var partition *structures.Partition
+	var indexPartition int
 	var ebr structures.EBR
+	var gptHeader *structures.GPTHeader
+	var gptEntries []structures.GPTEntry
+	indexEntry := -1
 
-	if partition == nil {
-		// Buscar lógica
-		ebrTemp, err := mbr.GetLogicalPartitionByName(mount.name, mount.path)
+	if isGPT {
+		// Buscar en la tabla GPT
+		var err error
+		gptHeader, gptEntries, err = structures.ReadGPT(mount.path)
 		if err != nil {
-			return "", errors.New("la partición no existe (ni primaria ni lógica)")
+			return "", fmt.Errorf("error leyendo la tabla GPT: %w", err)
 		}
-		isLogical = true
-		ebr = ebrTemp
-	}
+		_, indexEntry = structures.GetGPTPartitionByName(gptEntries, mount.name)
+		if indexEntry == -1 {
+			return "", errors.New("la partición no existe en la tabla GPT")
+		}
+	} else {
+		// Buscar partición primaria
+		partition, indexPartition = mbr.GetPartitionByName(mount.name)
 
-	
-	
+		if partition != nil && partition.Part_type[0] == 'E' {
+			return "", errors.New("no se puede montar una partición extendida directamente")
+		}
 
-	//valida si ya hay una particion montada
-	for _, info := range stores.MountedPartitions {
-		if info.Path == mount.path && strings.EqualFold(info.Name, mount.name) {
-			return "", fmt.Errorf("la partición '%s' ya está montada", mount.name)
+		if partition == nil {
+			// Buscar lógica
+			ebrTemp, err := mbr.GetLogicalPartitionByName(mount.name, mount.path)
+			if err != nil {
+				return "", errors.New("la partición no existe (ni primaria ni lógica)")
+			}
+			isLogical = true
+			ebr = ebrTemp
 		}
 	}
 
+	//valida si ya hay una particion montada
+	if stores.GetMountedIDByName(mount.path, mount.name) != "" {
+		return "", fmt.Errorf("la partición '%s' ya está montada", mount.name)
+	}
+
 	// Generar ID
 	id, correlative, letter, err := generatePartitionID(mount)
 	if err != nil {
 		return "", err
 	}
 
-	// Actualizar datos en estructura RAM
-	if isLogical {
+	// Si algo falla antes de registrar el montaje, el ID reservado vuelve a quedar libre
+	registered := false
+	defer func() {
+		if !registered {
+			utils.ReleasePartitionCorrelative(mount.path, correlative)
+		}
+	}()
+
+	// Actualizar el estado de montaje y escribirlo en disco
+	var partStart int64
+	if isGPT {
+		gptEntries[indexEntry].MountPartition(correlative, id)
+		if err := structures.WriteGPT(mount.path, gptHeader, gptEntries); err != nil {
+			return "", fmt.Errorf("error escribiendo la tabla GPT: %w", err)
+		}
+		partStart = int64(gptEntries[indexEntry].PartStart)
+	} else if isLogical {
 		ebr.PartMount = '1'
-		ebr.PrintEBR() 
-		// NO escribimos en disco, se queda solo en memoria
+		if err := structures.WriteEBR(mount.path, &ebr, int64(ebr.PartStart)); err != nil {
+			return "", fmt.Errorf("error escribiendo el EBR: %w", err)
+		}
+		partStart = int64(structures.NewMountedPartitionFromEBR(&ebr).Start)
 	} else {
 		partition.MountPartition(correlative, id)
+		mbr.Mbr_partitions[indexPartition] = *partition
+		if err := mbr.Serialize(mount.path); err != nil {
+			return "", fmt.Errorf("error serializando el MBR: %w", err)
+		}
+		partStart = int64(partition.Part_start)
+	}
+
+	// Si la partición ya fue formateada, registrar el montaje en el superbloque
+	var sb structures.SuperBlock
+	if err := sb.Deserialize(mount.path, partStart); err == nil && sb.S_magic == 0xEF53 {
+		sb.S_mnt_count++
+		sb.S_mtime = float32(time.Now().Unix())
+		if err := sb.Serialize(mount.path, partStart); err != nil {
+			return "", fmt.Errorf("error serializando el superbloque: %w", err)
+		}
 	}
 
 	// Guardar en RAM
-	stores.MountedPartitions[id] = stores.MountInfo{
+	stores.AddMountedPartition(id, stores.MountInfo{
 		Path:        mount.path,
 		Name:        mount.name,
 		Letter:      letter,
 		Correlative: correlative,
+	})
+	registered = true
+
+	// Registrar el disco en el catálogo por si fue creado antes de que existiera
+	stores.RegisterDisk(mount.path)
+
+	// Persistir la tabla de montaje para sobrevivir reinicios del servidor
+	if err := stores.SaveState(); err != nil {
+		fmt.Println("Advertencia: no se pudo guardar el estado de montaje:", err)
 	}
 
 	fmt.Println("Partición montada correctamente. ID:", id)
@@ -139,3 +168,90 @@ func generatePartitionID(mount *MOUNT) (string, int, string, error) {
 	id := fmt.Sprintf("%s%d%s", lastTwo, correlative, letter)
 	return id, correlative, letter, nil
 }
+
+// RestoreState recupera el catálogo de discos y las particiones montadas desde el archivo de estado.
+// Solo se restauran las entradas cuyo disco y partición todavía existen;
+// devuelve los IDs restaurados y los descartados.
+func RestoreState() ([]string, []string, error) {
+	state, err := stores.LoadState()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Restaurar el catálogo de discos que todavía existen
+	removedDisks := false
+	for path := range state.Disks {
+		if _, err := os.Stat(path); err != nil {
+			fmt.Printf("Descartando disco %s del catálogo: ya no existe\n", path)
+			removedDisks = true
+			continue
+		}
+		stores.RegisterDisk(path)
+	}
+
+	// Restaurar el registro de reportes cuyo archivo sigue existiendo
+	removedReports := false
+	for _, report := range state.Reports {
+		if _, err := os.Stat(report.Path); err != nil {
+			fmt.Printf("Descartando reporte %s del registro: %s ya no existe\n", report.ID, report.Path)
+			removedReports = true
+			continue
+		}
+		stores.RestoreReport(report)
+	}
+
+	// Restaurar en orden para que las letras y correlativos se asignen de forma estable
+	ids := make([]string, 0, len(state.MountedPartitions))
+	for id := range state.MountedPartitions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var restored, discarded []string
+	for _, id := range ids {
+		info := state.MountedPartitions[id]
+		if err := validateMountInfo(id, info); err != nil {
+			fmt.Printf("Descartando partición %s del estado: %v\n", id, err)
+			discarded = append(discarded, id)
+			continue
+		}
+		if err := utils.RegisterPartitionCorrelative(info.Path, info.Letter, info.Correlative); err != nil {
+			fmt.Printf("Descartando partición %s del estado: %v\n", id, err)
+			discarded = append(discarded, id)
+			continue
+		}
+		stores.AddMountedPartition(id, info)
+		restored = append(restored, id)
+	}
+
+	// Reescribir el estado sin las entradas inválidas
+	if len(discarded) > 0 || removedDisks || removedReports {
+		if err := stores.SaveState(); err != nil {
+			return restored, discarded, err
+		}
+	}
+
+	return restored, discarded, nil
+}
+
+// validateMountInfo verifica que una entrada del estado siga correspondiendo a una partición real
+func validateMountInfo(id string, info stores.MountInfo) error {
+	lastTwo := stores.Carnet[len(stores.Carnet)-2:]
+	if id != fmt.Sprintf("%s%d%s", lastTwo, info.Correlative, info.Letter) {
+		return errors.New("el ID no coincide con la letra y correlativo registrados")
+	}
+
+	if _, err := os.Stat(info.Path); err != nil {
+		return fmt.Errorf("el disco %s ya no existe", info.Path)
+	}
+
+	var mbr structures.MBR
+	if err := mbr.Deserialize(info.Path); err != nil {
+		return fmt.Errorf("error deserializando el MBR: %w", err)
+	}
+
+	if _, err := mbr.FindMountablePartition(info.Name, info.Path); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package commands
+
+import "sort"
+
+// Param es un parámetro de un comando ya separado por el lexer
+type Param struct {
+	Value string // Valor sin comillas; vacío si es una bandera
+	Flag  bool   // true si se escribió sin "=", ej: -p o -r
+}
+
+// Params son los parámetros de un comando indexados por nombre, en minúsculas y sin el guion
+type Params map[string]Param
+
+// Names devuelve los nombres de los parámetros en orden alfabético
+func (p Params) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Value devuelve el valor del parámetro, o "" si no se indicó
+func (p Params) Value(name string) string {
+	return p[name].Value
+}
+
+// Has indica si el parámetro fue indicado, con o sin valor
+func (p Params) Has(name string) bool {
+	_, ok := p[name]
+	return ok
+}
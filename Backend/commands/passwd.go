@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"backend/stores"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PASSWD estructura que representa el comando passwd con sus parámetros
+type PASSWD struct {
+	user    string // Usuario al que se le cambia la contraseña (solo root)
+	oldPass string // Contraseña actual del usuario con sesión
+	newPass string // Contraseña nueva
+}
+
+/*
+	passwd -old=123 -new=abc
+	passwd -user=user1 -new=abc
+*/
+
+func ParsePasswd(params Params, session *stores.AuthStore) (Result, error) {
+	cmd := &PASSWD{
+		user:    params.Value("user"),
+		oldPass: params.Value("old"),
+		newPass: params.Value("new"),
+	}
+
+	if strings.ContainsAny(cmd.newPass, ",\n") {
+		return Result{}, errors.New("la contraseña no puede contener comas ni saltos de línea")
+	}
+	if cmd.user == "" && cmd.oldPass == "" {
+		return Result{}, errors.New("faltan parámetros requeridos: -old o -user")
+	}
+	if cmd.user != "" && cmd.oldPass != "" {
+		return Result{}, errors.New("los parámetros -old y -user no se pueden usar juntos")
+	}
+
+	username, err := commandPasswd(cmd, session)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Message: fmt.Sprintf(
+		"\n=============================PASSWD=============================\n"+
+			"PASSWD: Contraseña actualizada para el usuario %s\n"+
+			"==================================================================",
+		username),
+		Data: SessionData{User: username},
+	}, nil
+}
+
+func commandPasswd(passwd *PASSWD, session *stores.AuthStore) (string, error) {
+	info := session.Info()
+	if !info.IsLoggedIn {
+		return "", errors.New("debe iniciar sesión para ejecutar este comando")
+	}
+
+	// Solo root puede cambiar la contraseña de otro usuario con -user
+	target := info.Username
+	if passwd.user != "" {
+		if info.Username != "root" {
+			return "", errors.New("solo el usuario root puede cambiar la contraseña de otro usuario")
+		}
+		target = passwd.user
+	}
+
+	unlock, err := stores.LockPartition(info.PartitionID)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición montada: %w", err)
+	}
+	defer unlock()
+
+	sb, mountedPartition, path, err := stores.GetMountedPartitionSuperblock(info.PartitionID)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición montada: %w", err)
+	}
+	mount, ok := stores.GetMountInfo(info.PartitionID)
+	if !ok {
+		return "", fmt.Errorf("error al obtener la partición montada: %s", info.PartitionID)
+	}
+
+	content, err := sb.ReadUsersFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error al leer users.txt: %w", err)
+	}
+
+	user := utils.ParseUsersFile(content).FindUser(target)
+	if user == nil {
+		return "", fmt.Errorf("el usuario %s no existe", target)
+	}
+
+	// -old cuenta como un intento de login: respeta el bloqueo y suma intentos fallidos
+	if passwd.oldPass != "" {
+		if err := checkPassword(mount, user, passwd.oldPass); err != nil {
+			return "", err
+		}
+	}
+
+	if err := savePassword(sb, path, mountedPartition.Start, content, user.Username, passwd.newPass); err != nil {
+		return "", fmt.Errorf("error al actualizar users.txt: %w", err)
+	}
+
+	return user.Username, nil
+}
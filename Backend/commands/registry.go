@@ -0,0 +1,441 @@
+package commands
+
+import (
+	"backend/stores"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParamType tipo de valor que acepta un parámetro
+type ParamType string
+
+const (
+	ParamString ParamType = "string" // Texto no vacío
+	ParamInt    ParamType = "int"    // Entero positivo
+	ParamFlag   ParamType = "flag"   // Bandera sin valor, ej: -p
+)
+
+// ParamSpec describe un parámetro que acepta un comando
+type ParamSpec struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Values      []string  `json:"values,omitempty"`  // Valores permitidos, sin distinguir mayúsculas
+	Default     string    `json:"default,omitempty"` // Valor usado si no se indica
+	Required    bool      `json:"required"`
+	Numbered    bool      `json:"numbered,omitempty"`   // Se escribe como -name1, -name2, ...
+	Positional  bool      `json:"positional,omitempty"` // Se escribe sin guion, ej: help mkdisk
+	Description string    `json:"description"`
+}
+
+// Handler ejecuta un comando con sus parámetros ya validados
+type Handler func(params Params, session *stores.AuthStore) (Result, error)
+
+// CommandSpec describe un comando: sus parámetros y la función que lo ejecuta
+type CommandSpec struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Usage       string      `json:"usage"`
+	Example     string      `json:"example,omitempty"`
+	Params      []ParamSpec `json:"params"`
+	Handler     Handler     `json:"-"`
+}
+
+// Comandos registrados, indexados por nombre
+var registry = map[string]*CommandSpec{}
+
+func init() {
+	for _, spec := range builtinCommands() {
+		spec.Usage = spec.buildUsage()
+		registry[spec.Name] = spec
+	}
+}
+
+// builtinCommands declara todos los comandos que entiende el analizador
+func builtinCommands() []*CommandSpec {
+	fits := []string{"BF", "FF", "WF"}
+	units := []string{"K", "M"}
+
+	return []*CommandSpec{
+		{
+			Name:        "mkdisk",
+			Description: "Crea un disco virtual .mia con su MBR o tabla GPT",
+			Example:     `mkdisk -size=10 -unit=M -path="/home/mis discos/Disco1.mia"`,
+			Params: []ParamSpec{
+				{Name: "size", Type: ParamInt, Required: true, Description: "Tamaño del disco"},
+				{Name: "path", Type: ParamString, Required: true, Description: "Ruta del archivo del disco"},
+				{Name: "unit", Type: ParamString, Values: units, Default: "M", Description: "Unidad del tamaño"},
+				{Name: "fit", Type: ParamString, Values: fits, Default: "FF", Description: "Ajuste de las particiones"},
+				{Name: "table", Type: ParamString, Values: []string{"MBR", "GPT"}, Default: "MBR", Description: "Tabla de particiones"},
+			},
+			Handler: withoutSession(ParseMkdisk),
+		},
+		{
+			Name:        "rmdisk",
+			Description: "Elimina un disco virtual y desmonta sus particiones",
+			Example:     `rmdisk -path="/home/mis discos/Disco1.mia"`,
+			Params: []ParamSpec{
+				{Name: "path", Type: ParamString, Required: true, Description: "Ruta del archivo del disco"},
+			},
+			Handler: withoutSession(ParseRmdisk),
+		},
+		{
+			Name:        "fdisk",
+			Description: "Crea una partición primaria, extendida o lógica",
+			Example:     `fdisk -size=300 -unit=K -path=/home/Disco1.mia -name=Particion1`,
+			Params: []ParamSpec{
+				{Name: "size", Type: ParamInt, Required: true, Description: "Tamaño de la partición"},
+				{Name: "path", Type: ParamString, Required: true, Description: "Ruta del archivo del disco"},
+				{Name: "name", Type: ParamString, Required: true, Description: "Nombre de la partición"},
+				{Name: "unit", Type: ParamString, Values: units, Default: "M", Description: "Unidad del tamaño"},
+				{Name: "fit", Type: ParamString, Values: fits, Default: "WF", Description: "Ajuste de la partición"},
+				{Name: "type", Type: ParamString, Values: []string{"P", "E", "L"}, Default: "P", Description: "Tipo de partición"},
+			},
+			Handler: withoutSession(ParseFdisk),
+		},
+		{
+			Name:        "mount",
+			Description: "Monta una partición y le asigna un ID",
+			Example:     `mount -path=/home/Disco1.mia -name=Particion1`,
+			Params: []ParamSpec{
+				{Name: "path", Type: ParamString, Required: true, Description: "Ruta del archivo del disco"},
+				{Name: "name", Type: ParamString, Required: true, Description: "Nombre de la partición"},
+			},
+			Handler: withoutSession(ParseMount),
+		},
+		{
+			Name:        "unmount",
+			Description: "Desmonta una partición y cierra sus sesiones",
+			Example:     `unmount -id=781A`,
+			Params: []ParamSpec{
+				{Name: "id", Type: ParamString, Required: true, Description: "ID de la partición montada"},
+			},
+			Handler: withoutSession(ParseUnmount),
+		},
+		{
+			Name:        "mounted",
+			Description: "Muestra las particiones montadas",
+			Handler: func(Params, *stores.AuthStore) (Result, error) {
+				var mounted []MountData
+				for id, info := range stores.ListMountedPartitions() {
+					mounted = append(mounted, MountData{ID: id, Path: info.Path, Name: info.Name})
+				}
+				sort.Slice(mounted, func(i, j int) bool { return mounted[i].ID < mounted[j].ID })
+				return Result{Message: stores.ShowMountedPartitions(), Data: mounted}, nil
+			},
+		},
+		{
+			Name:        "mkfs",
+			Description: "Formatea una partición montada con EXT2 o EXT3",
+			Example:     `mkfs -id=781A -type=full -fs=3fs`,
+			Params: []ParamSpec{
+				{Name: "id", Type: ParamString, Required: true, Description: "ID de la partición montada"},
+				{Name: "type", Type: ParamString, Values: []string{"full"}, Default: "full", Description: "Tipo de formateo"},
+				{Name: "fs", Type: ParamString, Values: []string{"2fs", "3fs"}, Default: "2fs", Description: "Sistema de archivos: 2fs (EXT2) o 3fs (EXT3, con journal)"},
+			},
+			Handler: withoutSession(ParseMkfs),
+		},
+		{
+			Name:        "rep",
+			Description: "Genera un reporte de un disco o de una partición montada",
+			Example:     `rep -id=781A -path=/home/reports/mbr.png -name=mbr`,
+			Params: []ParamSpec{
+				{Name: "id", Type: ParamString, Required: true, Description: "ID de la partición montada"},
+				{Name: "path", Type: ParamString, Required: true, Description: "Ruta del archivo del reporte; .svg lo dibuja sin Graphviz, .json guarda sus datos y los reportes de texto (bm_inode, bm_block, file) usan .txt"},
+				{Name: "name", Type: ParamString, Required: true, Values: reportNames, Description: "Tipo de reporte"},
+				{Name: "path_file_ls", Type: ParamString, Description: "Ruta dentro de la partición para los reportes file y ls"},
+			},
+			Handler: withoutSession(ParseRep),
+		},
+		{
+			Name:        "login",
+			Description: "Inicia sesión en una partición montada",
+			Example:     `login -user=root -pass=123 -id=781A`,
+			Params: []ParamSpec{
+				{Name: "user", Type: ParamString, Required: true, Description: "Nombre del usuario"},
+				{Name: "pass", Type: ParamString, Required: true, Description: "Contraseña"},
+				{Name: "id", Type: ParamString, Required: true, Description: "ID de la partición montada"},
+			},
+			Handler: ParseLogin,
+		},
+		{
+			Name:        "logout",
+			Description: "Cierra la sesión activa",
+			Handler:     ParseLogout,
+		},
+		{
+			Name:        "passwd",
+			Description: "Cambia la contraseña propia (-old) o la de otro usuario siendo root (-user)",
+			Example:     `passwd -old=123 -new=abc`,
+			Params: []ParamSpec{
+				{Name: "new", Type: ParamString, Required: true, Description: "Contraseña nueva"},
+				{Name: "old", Type: ParamString, Description: "Contraseña actual"},
+				{Name: "user", Type: ParamString, Description: "Usuario al que se le cambia la contraseña (solo root)"},
+			},
+			Handler: ParsePasswd,
+		},
+		{
+			Name:        "mkgrp",
+			Description: "Crea un grupo en users.txt (solo root)",
+			Example:     `mkgrp -name=usuarios`,
+			Params: []ParamSpec{
+				{Name: "name", Type: ParamString, Required: true, Description: "Nombre del grupo"},
+			},
+			Handler: ParseMkgrp,
+		},
+		{
+			Name:        "rmgrp",
+			Description: "Elimina un grupo de users.txt (solo root)",
+			Example:     `rmgrp -name=usuarios`,
+			Params: []ParamSpec{
+				{Name: "name", Type: ParamString, Required: true, Description: "Nombre del grupo"},
+			},
+			Handler: ParseRmgrp,
+		},
+		{
+			Name:        "mkdir",
+			Description: "Crea una carpeta en la partición de la sesión",
+			Example:     `mkdir -p -path="/home/mis documentos"`,
+			Params: []ParamSpec{
+				{Name: "path", Type: ParamString, Required: true, Description: "Ruta de la carpeta"},
+				{Name: "p", Type: ParamFlag, Description: "Crea también las carpetas padre que no existan"},
+			},
+			Handler: ParseMkdir,
+		},
+		{
+			Name:        "cat",
+			Description: "Muestra el contenido de uno o más archivos",
+			Example:     `cat -file1=/users.txt -file2=/home/notas.txt`,
+			Params: []ParamSpec{
+				{Name: "file", Type: ParamString, Required: true, Numbered: true, Description: "Ruta de cada archivo"},
+			},
+			Handler: ParseCat,
+		},
+		{
+			Name:        "help",
+			Description: "Muestra los comandos disponibles o los parámetros de uno",
+			Example:     `help mkdisk`,
+			Params: []ParamSpec{
+				{Name: "command", Type: ParamString, Positional: true, Description: "Comando a consultar"},
+			},
+			Handler: withoutSession(ParseHelp),
+		},
+	}
+}
+
+// withoutSession adapta los comandos que no usan la sesión
+func withoutSession(fn func(Params) (Result, error)) Handler {
+	return func(params Params, _ *stores.AuthStore) (Result, error) {
+		return fn(params)
+	}
+}
+
+// LookupCommand busca un comando por nombre, sin distinguir mayúsculas
+func LookupCommand(name string) (*CommandSpec, bool) {
+	spec, ok := registry[strings.ToLower(name)]
+	return spec, ok
+}
+
+// ListCommands devuelve los comandos registrados en orden alfabético
+func ListCommands() []*CommandSpec {
+	list := make([]*CommandSpec, 0, len(registry))
+	for _, spec := range registry {
+		list = append(list, spec)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Validate verifica los parámetros y argumentos posicionales contra la especificación del
+// comando y devuelve los parámetros normalizados, con los valores por defecto completados
+func (spec *CommandSpec) Validate(params Params, args []string) (Params, error) {
+	result := Params{}
+
+	// Asignar los argumentos posicionales en el orden declarado
+	positional := spec.positionalParams()
+	if len(args) > len(positional) {
+		return nil, fmt.Errorf("parámetro inválido: %s", args[len(positional)])
+	}
+	for i, arg := range args {
+		result[positional[i].Name] = Param{Value: arg}
+	}
+
+	for _, key := range params.Names() {
+		param := params[key]
+		ps := spec.lookupParam(key)
+		if ps == nil {
+			return nil, fmt.Errorf("parámetro desconocido: -%s", key)
+		}
+
+		if ps.Type == ParamFlag {
+			if !param.Flag {
+				return nil, fmt.Errorf("el parámetro -%s no debe llevar valor", key)
+			}
+			result[key] = param
+			continue
+		}
+
+		value, err := ps.check(key, param)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = Param{Value: value}
+	}
+
+	// Los parámetros numerados se leen desde -name1 hasta el primero que falte
+	for _, ps := range spec.Params {
+		if !ps.Numbered {
+			continue
+		}
+		if err := spec.checkNumbering(result, ps); err != nil {
+			return nil, err
+		}
+	}
+
+	// Verificar obligatorios y completar valores por defecto
+	var missing []string
+	for _, ps := range spec.Params {
+		if spec.hasParam(result, ps) {
+			continue
+		}
+		if ps.Required {
+			name := "-" + ps.Name
+			if ps.Numbered {
+				name += "1"
+			} else if ps.Positional {
+				name = ps.Name
+			}
+			missing = append(missing, name)
+		} else if ps.Default != "" {
+			result[ps.Name] = Param{Value: ps.Default}
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("faltan parámetros requeridos: %s", strings.Join(missing, ", "))
+	}
+
+	return result, nil
+}
+
+// check valida el valor de un parámetro y lo devuelve en su forma normalizada
+func (ps *ParamSpec) check(key string, param Param) (string, error) {
+	if param.Flag {
+		return "", fmt.Errorf("el parámetro -%s debe llevar un valor", key)
+	}
+	if param.Value == "" {
+		return "", fmt.Errorf("el parámetro -%s no puede estar vacío", key)
+	}
+
+	if ps.Type == ParamInt {
+		n, err := strconv.Atoi(param.Value)
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("el parámetro -%s debe ser un número entero positivo", key)
+		}
+	}
+
+	if len(ps.Values) == 0 {
+		return param.Value, nil
+	}
+	for _, allowed := range ps.Values {
+		if strings.EqualFold(allowed, param.Value) {
+			return allowed, nil
+		}
+	}
+	return "", fmt.Errorf("valor inválido para -%s: %s, debe ser uno de los siguientes: %s",
+		key, param.Value, strings.Join(ps.Values, ", "))
+}
+
+// lookupParam busca la especificación de un parámetro con nombre (no posicional)
+func (spec *CommandSpec) lookupParam(key string) *ParamSpec {
+	for i := range spec.Params {
+		ps := &spec.Params[i]
+		if ps.Positional {
+			continue
+		}
+		if ps.Numbered {
+			n, err := strconv.Atoi(strings.TrimPrefix(key, ps.Name))
+			if strings.HasPrefix(key, ps.Name) && err == nil && n >= 1 {
+				return ps
+			}
+		} else if ps.Name == key {
+			return ps
+		}
+	}
+	return nil
+}
+
+// hasParam indica si el parámetro fue indicado (para los numerados, basta con uno)
+func (spec *CommandSpec) hasParam(params Params, ps ParamSpec) bool {
+	if !ps.Numbered {
+		return params.Has(ps.Name)
+	}
+	for key := range params {
+		if spec.lookupParam(key) != nil && strings.HasPrefix(key, ps.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNumbering verifica que los parámetros numerados empiecen en 1 y no tengan saltos,
+// ej: -file1 y -file2 son válidos pero -file2 sin -file1 no
+func (spec *CommandSpec) checkNumbering(params Params, ps ParamSpec) error {
+	count := 0
+	for key := range params {
+		if spec.lookupParam(key) != nil && strings.HasPrefix(key, ps.Name) {
+			count++
+		}
+	}
+	for n := 1; n <= count; n++ {
+		if key := fmt.Sprintf("%s%d", ps.Name, n); !params.Has(key) {
+			return fmt.Errorf("los parámetros -%s deben numerarse desde -%s1 sin saltos: falta -%s", ps.Name, ps.Name, key)
+		}
+	}
+	return nil
+}
+
+// positionalParams devuelve los parámetros posicionales en el orden declarado
+func (spec *CommandSpec) positionalParams() []ParamSpec {
+	var positional []ParamSpec
+	for _, ps := range spec.Params {
+		if ps.Positional {
+			positional = append(positional, ps)
+		}
+	}
+	return positional
+}
+
+// buildUsage arma la línea de uso del comando, ej: mkdisk -size=<entero> [-unit=K|M]
+func (spec *CommandSpec) buildUsage() string {
+	parts := []string{spec.Name}
+	for _, ps := range spec.Params {
+		part := ps.usage()
+		if !ps.Required {
+			part = "[" + part + "]"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// usage devuelve cómo se escribe el parámetro, ej: -fit=BF|FF|WF
+func (ps *ParamSpec) usage() string {
+	value := "<texto>"
+	if len(ps.Values) > 0 {
+		value = strings.Join(ps.Values, "|")
+	} else if ps.Type == ParamInt {
+		value = "<entero>"
+	}
+
+	switch {
+	case ps.Positional:
+		return "<" + ps.Name + ">"
+	case ps.Type == ParamFlag:
+		return "-" + ps.Name
+	case ps.Numbered:
+		return fmt.Sprintf("-%s1=%s -%s2=%s ...", ps.Name, value, ps.Name, value)
+	default:
+		return "-" + ps.Name + "=" + value
+	}
+}
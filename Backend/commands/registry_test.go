@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testSpec es un comando de prueba con un parámetro de cada tipo
+var testSpec = &CommandSpec{
+	Name: "prueba",
+	Params: []ParamSpec{
+		{Name: "size", Type: ParamInt, Required: true},
+		{Name: "unit", Type: ParamString, Values: []string{"K", "M"}, Default: "M"},
+		{Name: "path", Type: ParamString},
+		{Name: "p", Type: ParamFlag},
+		{Name: "file", Type: ParamString, Numbered: true},
+		{Name: "command", Type: ParamString, Positional: true},
+	},
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc   string
+		params Params
+		args   []string
+		want   Params
+	}{
+		{
+			desc:   "valor por defecto",
+			params: Params{"size": {Value: "5"}},
+			want:   Params{"size": {Value: "5"}, "unit": {Value: "M"}},
+		},
+		{
+			desc:   "enum sin distinguir mayúsculas",
+			params: Params{"size": {Value: "5"}, "unit": {Value: "k"}},
+			want:   Params{"size": {Value: "5"}, "unit": {Value: "K"}},
+		},
+		{
+			desc:   "bandera y numerados",
+			params: Params{"size": {Value: "5"}, "p": {Flag: true}, "file1": {Value: "/a"}, "file2": {Value: "/b"}},
+			want:   Params{"size": {Value: "5"}, "unit": {Value: "M"}, "p": {Flag: true}, "file1": {Value: "/a"}, "file2": {Value: "/b"}},
+		},
+		{
+			desc:   "posicional",
+			params: Params{"size": {Value: "5"}},
+			args:   []string{"mkdisk"},
+			want:   Params{"size": {Value: "5"}, "unit": {Value: "M"}, "command": {Value: "mkdisk"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := testSpec.Validate(tt.params, tt.args)
+		if err != nil {
+			t.Errorf("%s: %v", tt.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %+v, se esperaba %+v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		desc   string
+		params Params
+		args   []string
+		want   string
+	}{
+		{"desconocido", Params{"size": {Value: "5"}, "color": {Value: "rojo"}}, nil, "desconocido: -color"},
+		{"faltante", Params{"unit": {Value: "K"}}, nil, "faltan parámetros requeridos: -size"},
+		{"enum", Params{"size": {Value: "5"}, "unit": {Value: "G"}}, nil, "valor inválido para -unit"},
+		{"entero", Params{"size": {Value: "-3"}}, nil, "entero positivo"},
+		{"vacío", Params{"size": {Value: "5"}, "path": {Value: ""}}, nil, "no puede estar vacío"},
+		{"valor sin =", Params{"size": {Flag: true}}, nil, "debe llevar un valor"},
+		{"bandera con valor", Params{"size": {Value: "5"}, "p": {Value: "1"}}, nil, "no debe llevar valor"},
+		{"numerado sin 1", Params{"size": {Value: "5"}, "file2": {Value: "/b"}}, nil, "falta -file1"},
+		{"numerado con salto", Params{"size": {Value: "5"}, "file1": {Value: "/a"}, "file3": {Value: "/c"}}, nil, "falta -file2"},
+		{"numerado en 0", Params{"size": {Value: "5"}, "file0": {Value: "/a"}}, nil, "desconocido: -file0"},
+		{"posicional de más", Params{"size": {Value: "5"}}, []string{"a", "b"}, "parámetro inválido: b"},
+	}
+
+	for _, tt := range tests {
+		_, err := testSpec.Validate(tt.params, tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, se esperaba que contenga %q", tt.desc, err, tt.want)
+		}
+	}
+}
@@ -4,91 +4,50 @@ package commands
 import (
 	reports "backend/reportes"
 	stores "backend/stores"
+	structures "backend/structures"
+	utils "backend/utils"
 	"errors"
 	"fmt"
-	"regexp"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
+// ErrInvalidReport indica que los parámetros del reporte pedido no son válidos
+var ErrInvalidReport = errors.New("parámetros de reporte inválidos")
+
 // REP estructura que representa el comando rep con sus parámetros
 type REP struct {
-	id           string // ID del disco
-	path         string // Ruta del archivo del disco
-	name         string // Nombre del reporte
-	path_file_ls string // Ruta del archivo ls (opcional)
+	id           string            // ID del disco
+	path         string            // Ruta del archivo del disco
+	name         string            // Nombre del reporte
+	path_file_ls string            // Ruta del archivo ls (opcional)
+	session      *stores.AuthStore // Sesión que pide el reporte por HTTP; nil desde la terminal
 }
 
 // ParserRep parsea el comando rep y devuelve una instancia de REP
-func ParseRep(tokens []string) (string, error) {
-	cmd := &REP{} // Crea una nueva instancia de REP
-
-	// Unir tokens en una sola cadena y luego dividir por espacios, respetando las comillas
-	args := strings.Join(tokens, " ")
-	// Expresión regular para encontrar los parámetros del comando rep
-	re := regexp.MustCompile(`-id=[^\s]+|-path="[^"]+"|-path=[^\s]+|-name=[^\s]+|-path_file_ls="[^"]+"|-path_file_ls=[^\s]+`)
-	// Encuentra todas las coincidencias de la expresión regular en la cadena de argumentos
-	matches := re.FindAllString(args, -1)
-
-	// Itera sobre cada coincidencia encontrada
-	for _, match := range matches {
-		// Divide cada parte en clave y valor usando "=" como delimitador
-		kv := strings.SplitN(match, "=", 2)
-		if len(kv) != 2 {
-			return "", fmt.Errorf("formato de parámetro inválido: %s", match)
-		}
-		key, value := strings.ToLower(kv[0]), kv[1]
-
-		// Remove quotes from value if present
-		if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-			value = strings.Trim(value, "\"")
-		}
-
-		// Switch para manejar diferentes parámetros
-		switch key {
-		case "-id":
-			// Verifica que el id no esté vacío
-			if value == "" {
-				return "", errors.New("el id no puede estar vacío")
-			}
-			cmd.id = value
-		case "-path":
-			// Verifica que el path no esté vacío
-			if value == "" {
-				return "", errors.New("el path no puede estar vacío")
-			}
-			cmd.path = value
-		case "-name":
-			// Verifica que el nombre sea uno de los valores permitidos
-			validNames := []string{"mbr", "disk", "inode", "block", "bm_inode", "bm_block", "sb", "file", "ls", "tree"}
-			if !contains(validNames, value) {
-				return "", errors.New("nombre inválido, debe ser uno de los siguientes: mbr, disk, inode, block, bm_inode, bm_block, sb, file, ls")
-			}
-			cmd.name = value
-		case "-path_file_ls":
-			cmd.path_file_ls = value
-		default:
-			// Si el parámetro no es reconocido, devuelve un error
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	// Verifica que los parámetros obligatorios hayan sido proporcionados
-	if cmd.id == "" || cmd.path == "" || cmd.name == "" {
-		return "", errors.New("faltan parámetros requeridos: -id, -path, -name")
+func ParseRep(params Params) (Result, error) {
+	cmd := &REP{
+		id:           params.Value("id"),
+		path:         params.Value("path"),
+		name:         params.Value("name"),
+		path_file_ls: params.Value("path_file_ls"),
 	}
 
 	// Aquí se puede agregar la lógica para ejecutar el comando rep con los parámetros proporcionados
-	err := commandRep(cmd)
+	report, err := runRep(cmd)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf(
+	return Result{Message: fmt.Sprintf(
 		"=================================================================\n"+
 		"REP: Reporte generado exitosamente\n"+
 		"-> ID:    %s\n"+
 		"-> Path:  %s\n"+
 		"-> Tipo:  %s%s\n"+
+		"-> Reporte: %s\n"+
 		"%s"+
 		"=================================================================",
 		cmd.id,
@@ -100,36 +59,114 @@ func ParseRep(tokens []string) (string, error) {
 			}
 			return ""
 		}(),
+		report.ID,
 		"", // línea vacía opcional para separar visualmente
-	), nil
+	),
+		Data: ReportData{ID: cmd.id, Path: cmd.path, Name: cmd.name, PathFileLs: cmd.path_file_ls, ReportID: report.ID},
+	}, nil
 }	
 
-// Función auxiliar para verificar si un valor está en una lista
-func contains(list []string, value string) bool {
-	for _, v := range list {
-		if v == value {
-			return true
-		}
+// GenerateReport genera un reporte de la partición de la sesión, igual que el comando rep,
+// y devuelve su entrada en el registro de reportes. El archivo queda en la carpeta de
+// reportes con un nombre elegido aquí; format es svg, png o json para los reportes
+// gráficos y txt o json para los de texto (vacío usa el primero).
+func GenerateReport(session *stores.AuthStore, id string, name string, format string, pathFileLs string) (stores.ReportInfo, error) {
+	if err := checkReportSession(session, id); err != nil {
+		return stores.ReportInfo{}, err
 	}
-	return false
+	if !isReportName(name) {
+		return stores.ReportInfo{}, fmt.Errorf("%w: reporte desconocido: %s", ErrInvalidReport, name)
+	}
+	if _, ok := stores.GetMountInfo(id); !ok {
+		return stores.ReportInfo{}, fmt.Errorf("%w: partición %s no montada", ErrNotFound, id)
+	}
+
+	formats := []string{"txt", "json"}
+	if imageReports[name] {
+		formats = []string{"svg", "png", "json"}
+	}
+	format = strings.ToLower(format)
+	if format == "" {
+		format = formats[0]
+	}
+	if !slices.Contains(formats, format) {
+		return stores.ReportInfo{}, fmt.Errorf("%w: el reporte %s acepta los formatos %s", ErrInvalidReport, name, strings.Join(formats, ", "))
+	}
+
+	fileName := fmt.Sprintf("%s_%s_%d.%s", id, name, time.Now().UnixNano(), format)
+	return runRep(&REP{id: id, path: filepath.Join(stores.ReportsDir(), fileName), name: name, path_file_ls: pathFileLs, session: session})
+}
+
+// runRep genera el reporte y lo anota en el registro de reportes
+func runRep(rep *REP) (stores.ReportInfo, error) {
+	if err := commandRep(rep); err != nil {
+		return stores.ReportInfo{}, err
+	}
+
+	report, err := stores.RegisterReport(rep.id, rep.name, rep.path)
+	if err != nil {
+		return stores.ReportInfo{}, fmt.Errorf("reporte generado, pero no se pudo registrar: %v", err)
+	}
+	if err := stores.SaveState(); err != nil {
+		fmt.Println("Advertencia: no se pudo guardar el registro de reportes:", err)
+	}
+	return report, nil
+}
+
+// Nombres de reporte que acepta el parámetro -name
+var reportNames = []string{"mbr", "disk", "inode", "block", "bm_inode", "bm_block", "sb", "file", "ls", "tree", "journaling"}
+
+// Reportes que generan una imagen con Graphviz; los demás son archivos de texto
+var imageReports = map[string]bool{
+	"mbr": true, "disk": true, "inode": true, "block": true, "sb": true, "ls": true, "tree": true, "journaling": true,
 }
 
 // Ejemplo de función commandRep (debe ser implementada)
 func commandRep(rep *REP) error {
+	// Los reportes de texto (bitmaps y file) no se pueden guardar como imagen
+	if isReportName(rep.name) && !imageReports[rep.name] && reports.IsImagePath(rep.path) {
+		return fmt.Errorf("el reporte %s genera texto: use una ruta .txt o .json", rep.name)
+	}
+
+	// Los reportes solo leen el disco
+	unlock, err := stores.RLockPartition(rep.id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Obtener la partición montada
-	mountedMbr, mountedSb, mountedDiskPath, err := stores.GetMountedPartitionRep(rep.id)
+	src, err := reportSource(rep.id, rep.path_file_ls)
 	if err != nil {
 		return err
 	}
+	mountedMbr, mountedSb, mountedDiskPath := src.MBR, src.SuperBlock, src.DiskPath
+
+	if rep.session != nil {
+		if err := checkReportPath(rep.session, rep.name, src); err != nil {
+			return err
+		}
+	}
+
+	// Con extensión .json se guardan los datos del reporte en lugar de dibujarlo
+	if reports.IsJSONPath(rep.path) {
+		data, err := reports.Data(rep.name, src)
+		if err != nil {
+			fmt.Printf("Error generando reporte %s: %v\n", strings.ToUpper(rep.name), err)
+			return err
+		}
+		return reports.WriteJSON(rep.path, data)
+	}
 
 	// Switch para manejar diferentes tipos de reportes
 	switch rep.name {
 	case "mbr":
 		err = reports.ReportMBR(mountedMbr, rep.path, mountedDiskPath)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			fmt.Printf("Error generando reporte MBR: %v\n", err)
+			return err
 		}
-	
+
 	case "disk":
 		err = reports.ReportDISK(mountedMbr, rep.path, mountedDiskPath)
 		if err != nil {
@@ -140,16 +177,162 @@ func commandRep(rep *REP) error {
 	case "inode":
 		err = reports.ReportInode(mountedSb, mountedDiskPath, rep.path)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			fmt.Printf("Error generando reporte INODE: %v\n", err)
+			return err
+		}
+	case "block":
+		err = reports.ReportBlock(mountedSb, mountedDiskPath, rep.path)
+		if err != nil {
+			fmt.Printf("Error generando reporte BLOCK: %v\n", err)
+			return err
+		}
+	case "bm_block":
+		err = reports.ReportBMBlock(mountedSb, mountedDiskPath, rep.path)
+		if err != nil {
+			fmt.Printf("Error generando reporte BM_BLOCK: %v\n", err)
+			return err
+		}
+	case "sb":
+		err = reports.ReportSB(mountedSb, src.PartitionStart, rep.path)
+		if err != nil {
+			fmt.Printf("Error generando reporte SB: %v\n", err)
+			return err
+		}
+	case "tree":
+		err = reports.ReportTree(mountedSb, mountedDiskPath, rep.path)
+		if err != nil {
+			fmt.Printf("Error generando reporte TREE: %v\n", err)
+			return err
+		}
+	case "file":
+		err = reports.ReportFile(mountedSb, mountedDiskPath, rep.path, rep.path_file_ls)
+		if err != nil {
+			fmt.Printf("Error generando reporte FILE: %v\n", err)
+			return err
+		}
+	case "ls":
+		err = reports.ReportLs(mountedSb, mountedDiskPath, rep.path, rep.path_file_ls)
+		if err != nil {
+			fmt.Printf("Error generando reporte LS: %v\n", err)
+			return err
+		}
+	case "journaling":
+		err = reports.ReportJournaling(mountedSb, src.PartitionStart, mountedDiskPath, rep.path)
+		if err != nil {
+			fmt.Printf("Error generando reporte JOURNALING: %v\n", err)
+			return err
 		}
 	case "bm_inode":
 		err = reports.ReportBMInode(mountedSb, mountedDiskPath, rep.path)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			fmt.Printf("Error generando reporte BM_INODE: %v\n", err)
+			return err
 		}
+	default:
+		return fmt.Errorf("reporte desconocido: %s", rep.name)
 	}
 
-	
+	// Sin Graphviz las imágenes se generan en SVG
+	if imageReports[rep.name] {
+		rep.path = reports.ImagePath(rep.path)
+	}
+	return nil
+}
+
+// reportSource lee el MBR, el superbloque y el inicio de la partición montada
+func reportSource(id string, pathFileLs string) (reports.Source, error) {
+	mbr, sb, diskPath, err := stores.GetMountedPartitionRep(id)
+	if err != nil {
+		return reports.Source{}, err
+	}
+	partition, _, err := stores.GetMountedPartition(id)
+	if err != nil {
+		return reports.Source{}, err
+	}
 
+	return reports.Source{
+		MBR:            mbr,
+		SuperBlock:     sb,
+		DiskPath:       diskPath,
+		PartitionStart: partition.Start,
+		PathFileLs:     pathFileLs,
+	}, nil
+}
+
+// ReportJSON devuelve los datos del reporte de la partición de la sesión, los mismos que
+// guarda rep con una ruta .json
+func ReportJSON(session *stores.AuthStore, id string, name string, pathFileLs string) (any, error) {
+	if err := checkReportSession(session, id); err != nil {
+		return nil, err
+	}
+	if !isReportName(name) {
+		return nil, fmt.Errorf("%w: reporte %s", ErrNotFound, name)
+	}
+
+	unlock, err := stores.RLockPartition(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	defer unlock()
+
+	src, err := reportSource(id, pathFileLs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	if err := checkReportPath(session, name, src); err != nil {
+		return nil, err
+	}
+	return reports.Data(name, src)
+}
+
+// checkReportSession verifica que haya una sesión iniciada en la partición del reporte
+func checkReportSession(session *stores.AuthStore, id string) error {
+	info := session.Info()
+	if !info.IsLoggedIn {
+		return ErrNotAuthenticated
+	}
+	if info.PartitionID != id {
+		return fmt.Errorf("%w: la sesión activa pertenece a otra partición", ErrPermissionDenied)
+	}
+	return nil
+}
+
+// checkReportPath verifica que la sesión pueda leer la ruta de los reportes file y ls,
+// que muestran contenido de la partición; los demás reportes no dependen de una ruta
+func checkReportPath(session *stores.AuthStore, name string, src reports.Source) error {
+	if name != "file" && name != "ls" {
+		return nil
+	}
+	if src.PathFileLs == "" {
+		return fmt.Errorf("%w: el reporte %s requiere path_file_ls", ErrInvalidReport, name)
+	}
+
+	index, err := structures.FindInodeByPath(src.DiskPath, src.PathFileLs, *src.SuperBlock)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, src.PathFileLs)
+	}
+	inode, err := src.SuperBlock.ReadInode(src.DiskPath, index)
+	if err != nil {
+		return err
+	}
+	if name == "file" && inode.I_type[0] != '1' {
+		return fmt.Errorf("%w: %s no es un archivo", ErrInvalidReport, src.PathFileLs)
+	}
+	if name == "ls" && inode.I_type[0] != '0' {
+		return fmt.Errorf("%w: %s no es una carpeta", ErrInvalidReport, src.PathFileLs)
+	}
+	if !utils.HasReadPermission(session, *inode) {
+		return fmt.Errorf("%w: no tiene permiso de lectura en %s", ErrPermissionDenied, src.PathFileLs)
+	}
 	return nil
 }
+
+// isReportName indica si el nombre es uno de los reportes que acepta -name
+func isReportName(name string) bool {
+	for _, reportName := range reportNames {
+		if reportName == name {
+			return true
+		}
+	}
+	return false
+}
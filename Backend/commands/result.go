@@ -0,0 +1,74 @@
+package commands
+
+// Result es lo que devuelve un comando: el texto para la consola y los datos para el frontend
+type Result struct {
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// DiskData datos del disco creado o eliminado (mkdisk, rmdisk)
+type DiskData struct {
+	Path  string `json:"path"`
+	Name  string `json:"name"`
+	Size  int    `json:"size,omitempty"`
+	Unit  string `json:"unit,omitempty"`
+	Fit   string `json:"fit,omitempty"`
+	Table string `json:"table,omitempty"`
+}
+
+// PartitionData datos de la partición creada (fdisk)
+type PartitionData struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	Size int    `json:"size"`
+	Unit string `json:"unit"`
+	Type string `json:"type"`
+	Fit  string `json:"fit"`
+}
+
+// MountData datos de una partición montada o desmontada (mount, unmount, mounted)
+type MountData struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	Name string `json:"name"`
+}
+
+// FormatData datos del formateo de una partición (mkfs)
+type FormatData struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Filesystem string `json:"filesystem"`
+}
+
+// ReportData datos del reporte generado (rep)
+type ReportData struct {
+	ID         string `json:"id"`
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	PathFileLs string `json:"path_file_ls,omitempty"`
+	ReportID   string `json:"report_id"` // ID para descargarlo en GET /reports/:rid
+}
+
+// SessionData datos de la sesión iniciada o cerrada (login, logout, passwd)
+type SessionData struct {
+	User        string `json:"user"`
+	PartitionID string `json:"partition_id,omitempty"`
+}
+
+// GroupData datos del grupo creado o eliminado (mkgrp, rmgrp)
+type GroupData struct {
+	Name string `json:"name"`
+}
+
+// FolderData datos de la carpeta creada (mkdir)
+type FolderData struct {
+	Path    string `json:"path"`
+	Parents bool   `json:"parents"`
+}
+
+// CatFile contenido, o error, de cada archivo pedido a cat
+type CatFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
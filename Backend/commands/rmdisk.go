@@ -1,11 +1,11 @@
 package commands
 
 import (
-	"errors"
+	stores "backend/stores"
+	utils "backend/utils"
 	"fmt"
 	"os"
-	"regexp"
-	"strings"
+	"path/filepath"
 )
 
 // RMDISK estructura que representa el comando rmdisk con su parámetro
@@ -13,60 +13,31 @@ type RMDISK struct {
 	path string // Ruta del archivo del disco a eliminar
 }
 
-// ParseRmdisk recibe los tokens del comando y ejecuta el proceso
-func ParseRmdisk(tokens []string) (string, error) {
-	cmd := &RMDISK{}
-
-	// Unir tokens y usar expresión regular para encontrar -path="..." o -path=...
-	args := strings.Join(tokens, " ")
-	re := regexp.MustCompile(`-path="[^"]+"|-path=[^\s]+`)
-	matches := re.FindAllString(args, -1)
-
-	// Procesar parámetros
-	for _, match := range matches {
-		kv := strings.SplitN(match, "=", 2)
-		if len(kv) != 2 {
-			return "", fmt.Errorf("formato de parámetro inválido: %s", match)
-		}
-		key, value := strings.ToLower(kv[0]), kv[1]
-
-		// Quitar comillas si tiene
-		if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-			value = strings.Trim(value, "\"")
-		}
-
-		switch key {
-		case "-path":
-			if value == "" {
-				return "", errors.New("el path no puede estar vacío")
-			}
-			cmd.path = value
-		default:
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	// Validar que el path se haya definido
-	if cmd.path == "" {
-		return "", errors.New("faltan parámetros requeridos: -path")
-	}
+// ParseRmdisk recibe los parámetros del comando y ejecuta el proceso
+func ParseRmdisk(params Params) (Result, error) {
+	cmd := &RMDISK{path: filepath.Clean(params.Value("path"))}
 
 	// Ejecutar eliminación
 	err := commandRmdisk(cmd)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf(
+	return Result{Message: fmt.Sprintf(
 		"========================== RMDISK ===============================\n"+
 			"RMDISK: Disco eliminado correctamente\n"+
 			"-> Path: %s\n"+
 			"=================================================================",
-		cmd.path), nil
+		cmd.path),
+		Data: DiskData{Path: cmd.path, Name: filepath.Base(cmd.path)},
+	}, nil
 }
 
 // commandRmdisk elimina el archivo del disco si existe
 func commandRmdisk(rmdisk *RMDISK) error {
+	unlock := stores.LockDisk(rmdisk.path)
+	defer unlock()
+
 	// Verificar si el archivo existe
 	if _, err := os.Stat(rmdisk.path); os.IsNotExist(err) {
 		return fmt.Errorf("el archivo no existe en la ruta especificada: %s", rmdisk.path)
@@ -81,5 +52,17 @@ func commandRmdisk(rmdisk *RMDISK) error {
 		return fmt.Errorf("no se pudo eliminar el archivo: %v", err)
 	}
 
+	// Quitar del catálogo y de la tabla de montaje las particiones del disco eliminado
+	stores.UnregisterDisk(rmdisk.path)
+	for _, id := range stores.GetMountedIDsByDisk(rmdisk.path) {
+		info, _ := stores.GetMountInfo(id)
+		stores.LogoutPartition(id)
+		stores.RemoveMountedPartition(id)
+		utils.ReleasePartitionCorrelative(info.Path, info.Correlative)
+	}
+	if err := stores.SaveState(); err != nil {
+		fmt.Println("Advertencia: no se pudo guardar el catálogo de discos:", err)
+	}
+
 	return nil
 }
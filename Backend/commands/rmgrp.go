@@ -2,10 +2,8 @@ package commands
 
 import (
 	"backend/stores"
-	"backend/structures"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -15,60 +13,48 @@ type RMGRP struct {
 }
 
 // ParseRmgrp analiza los parámetros
-func ParseRmgrp(tokens []string) (string, error) {
-	cmd := &RMGRP{}
+func ParseRmgrp(params Params, session *stores.AuthStore) (Result, error) {
+	cmd := &RMGRP{name: params.Value("name")}
 
-	args := strings.Join(tokens, " ")
-	re := regexp.MustCompile(`-name="[^"]+"|-name=[^\s]+`)
-	matches := re.FindAllString(args, -1)
-
-	for _, match := range matches {
-		kv := strings.SplitN(match, "=", 2)
-		key := strings.ToLower(kv[0])
-		value := strings.Trim(kv[1], "\"")
-
-		if key == "-name" {
-			cmd.name = value
-		} else {
-			return "", fmt.Errorf("parámetro no reconocido: %s", key)
-		}
-	}
-
-	if cmd.name == "" {
-		return "", errors.New("el parámetro -name es obligatorio")
-	}
-
-	err := commandRmgrp(cmd)
+	err := commandRmgrp(cmd, session)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return fmt.Sprintf("Grupo '%s' eliminado correctamente.", cmd.name), nil
+	return Result{Message: fmt.Sprintf("Grupo '%s' eliminado correctamente.", cmd.name),
+		Data: GroupData{Name: cmd.name},
+	}, nil
 }
 
 
-func commandRmgrp(cmd *RMGRP) error {
+func commandRmgrp(cmd *RMGRP, session *stores.AuthStore) error {
 	// Verificar sesión
-	if !stores.Auth.IsAuthenticated() {
+	info := session.Info()
+	if !info.IsLoggedIn {
 		return errors.New("debe iniciar sesión para ejecutar este comando")
 	}
-	if stores.Auth.Username != "root" {
+	if info.Username != "root" {
 		return errors.New("solo el usuario root puede eliminar grupos")
 	}
 
-	// Obtener SuperBlock y path
-	sb, _, path, err := stores.GetMountedPartitionSuperblock(stores.Auth.PartitionID)
+	unlock, err := stores.LockPartition(info.PartitionID)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	// Obtener el bloque de users.txt
-	usersBlock, err := sb.GetUsersBlock(path)
+	// Obtener SuperBlock y path
+	sb, mountedPartition, path, err := stores.GetMountedPartitionSuperblock(info.PartitionID)
 	if err != nil {
 		return err
 	}
 
-	content := strings.Trim(string(usersBlock.B_content[:]), "\x00")
+	// Leer users.txt completo
+	content, err := sb.ReadUsersFile(path)
+	if err != nil {
+		return err
+	}
+	content = strings.Trim(content, "\x00")
 	lines := strings.Split(content, "\n")
 
 	found := false
@@ -94,26 +80,9 @@ func commandRmgrp(cmd *RMGRP) error {
 
 	newContent := strings.Join(lines, "\n")
 
-	var newBlock [64]byte
-	copy(newBlock[:], []byte(newContent))
-	copy(usersBlock.B_content[:], newBlock[:])
-
-	// Obtener el inodo para obtener la posición real del bloque
-	inode := &structures.Inode{}
-	err = inode.Deserialize(path, int64(sb.S_inode_start+sb.S_inode_size)) // inodo 1
-	if err != nil {
-		return err
-	}
-
-	blockIndex := inode.I_block[0]
-	if blockIndex == -1 {
-		return fmt.Errorf("el archivo users.txt no tiene bloque asignado")
-	}
-
-	err = usersBlock.Serialize(path, int64(sb.S_block_start)+int64(blockIndex)*int64(sb.S_block_size))
-	if err != nil {
+	if err := sb.WriteUsersFile(path, newContent); err != nil {
 		return err
 	}
 
-	return nil
+	return sb.Serialize(path, int64(mountedPartition.Start))
 }
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	stores "backend/stores"
+	structures "backend/structures"
+	utils "backend/utils"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UNMOUNT estructura que representa el comando unmount con sus parámetros
+type UNMOUNT struct {
+	id string // ID de la partición montada
+}
+
+/*
+	unmount -id=781A
+*/
+
+func ParseUnmount(params Params) (Result, error) {
+	cmd := &UNMOUNT{id: params.Value("id")}
+
+	info, err := commandUnmount(cmd)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Message: fmt.Sprintf(
+		"========================== UNMOUNT =============================\n"+
+			"UNMOUNT: Partición desmontada\n"+
+			"-> Path    : %s\n"+
+			"-> Nombre  : %s\n"+
+			"-> ID      : %s\n"+
+			"=================================================================\n",
+		info.Path, info.Name, cmd.id),
+		Data: MountData{ID: cmd.id, Path: info.Path, Name: info.Name},
+	}, nil
+}
+
+func commandUnmount(unmount *UNMOUNT) (stores.MountInfo, error) {
+	info, ok := stores.GetMountInfo(unmount.id)
+	if !ok {
+		return stores.MountInfo{}, fmt.Errorf("la partición con id '%s' no está montada", unmount.id)
+	}
+
+	unlock := stores.LockDisk(info.Path)
+	defer unlock()
+
+	// Otro unmount (o un mount que reutilizó el ID) pudo cambiar la entrada mientras se
+	// esperaba el candado; solo se sigue si es la misma partición
+	if current, ok := stores.GetMountInfo(unmount.id); !ok || current != info {
+		return stores.MountInfo{}, fmt.Errorf("la partición con id '%s' no está montada", unmount.id)
+	}
+
+	var mbr structures.MBR
+	if err := mbr.Deserialize(info.Path); err != nil {
+		return stores.MountInfo{}, fmt.Errorf("error deserializando el MBR: %w", err)
+	}
+
+	// Inicio de la partición, para actualizar el superbloque si está formateada
+	var partStart int64
+
+	if mbr.IsGPT() {
+		header, entries, err := structures.ReadGPT(info.Path)
+		if err != nil {
+			return stores.MountInfo{}, fmt.Errorf("error leyendo la tabla GPT: %w", err)
+		}
+		entry, index := structures.GetGPTPartitionByName(entries, info.Name)
+		if entry == nil {
+			return stores.MountInfo{}, errors.New("la partición no existe en la tabla GPT")
+		}
+
+		// Reiniciar el estado de montaje de la entrada GPT
+		entries[index].UnmountPartition()
+		if err := structures.WriteGPT(info.Path, header, entries); err != nil {
+			return stores.MountInfo{}, fmt.Errorf("error escribiendo la tabla GPT: %w", err)
+		}
+		partStart = int64(entries[index].PartStart)
+	} else if partition, index := mbr.GetPartitionByName(info.Name); partition != nil {
+		// Reiniciar el estado de montaje de la partición primaria en el MBR
+		mbr.Mbr_partitions[index].UnmountPartition()
+		if err := mbr.Serialize(info.Path); err != nil {
+			return stores.MountInfo{}, fmt.Errorf("error serializando el MBR: %w", err)
+		}
+		partStart = int64(partition.Part_start)
+	} else {
+		ebr, err := mbr.GetLogicalPartitionByName(info.Name, info.Path)
+		if err != nil {
+			return stores.MountInfo{}, errors.New("la partición no existe (ni primaria ni lógica)")
+		}
+
+		// Reiniciar el estado de montaje de la partición lógica en su EBR
+		ebr.PartMount = '0'
+		if err := structures.WriteEBR(info.Path, &ebr, int64(ebr.PartStart)); err != nil {
+			return stores.MountInfo{}, fmt.Errorf("error escribiendo el EBR: %w", err)
+		}
+		partStart = int64(structures.NewMountedPartitionFromEBR(&ebr).Start)
+	}
+
+	// Registrar la fecha de desmontaje solo si la partición tiene un sistema de archivos
+	var sb structures.SuperBlock
+	if err := sb.Deserialize(info.Path, partStart); err == nil && sb.S_magic == 0xEF53 {
+		sb.S_umtime = float32(time.Now().Unix())
+		if err := sb.Serialize(info.Path, partStart); err != nil {
+			return stores.MountInfo{}, fmt.Errorf("error serializando el superbloque: %w", err)
+		}
+	}
+
+	// Cerrar las sesiones abiertas en esta partición
+	stores.LogoutPartition(unmount.id)
+
+	// Quitar de RAM y liberar el correlativo para que pueda reutilizarse
+	stores.RemoveMountedPartition(unmount.id)
+	utils.ReleasePartitionCorrelative(info.Path, info.Correlative)
+
+	if err := stores.SaveState(); err != nil {
+		fmt.Println("Advertencia: no se pudo guardar el estado de montaje:", err)
+	}
+
+	fmt.Println("Partición desmontada correctamente. ID:", unmount.id)
+	return info, nil
+}
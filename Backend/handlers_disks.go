@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"backend/stores"
+	"backend/structures"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ---------- ESTRUCTURAS ----------
+type DiskResponse struct {
+	Name              string   `json:"name"`
+	Path              string   `json:"path"`
+	Size              int32    `json:"size"`
+	Fit               string   `json:"fit"`
+	Signature         int32    `json:"signature"`
+	CreationDate      string   `json:"creation_date"`
+	Table             string   `json:"table"`
+	MountedPartitions []string `json:"mounted_partitions"`
+}
+
+type PartitionResponse struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Fit        string `json:"fit"`
+	Status     string `json:"status"`
+	Start      int32  `json:"start"`
+	End        int32  `json:"end"`
+	Size       int32  `json:"size"`
+	MountID    string `json:"mount_id"`
+	Filesystem string `json:"filesystem"`
+}
+
+// ---------- HANDLER: GET /disks ----------
+func handleDisks(c *fiber.Ctx) error {
+	disks := []DiskResponse{}
+
+	for _, disk := range stores.ListDisks() {
+		var mbr structures.MBR
+		unlock := stores.RLockDisk(disk.Path)
+		err := mbr.Deserialize(disk.Path)
+		unlock()
+		if err != nil {
+			// El disco pudo ser borrado fuera del sistema, no se lista
+			continue
+		}
+		disks = append(disks, newDiskResponse(disk, &mbr))
+	}
+
+	return c.JSON(disks)
+}
+
+// ---------- HANDLER: GET /disks/:name/partitions ----------
+func handleDiskPartitions(c *fiber.Ctx) error {
+	disk, ok := stores.GetDiskByName(c.Params("name"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Disco no encontrado")
+	}
+
+	unlock := stores.RLockDisk(disk.Path)
+	defer unlock()
+
+	_, partitions, err := structures.ListPartitions(disk.Path)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer las particiones del disco")
+	}
+
+	response := []PartitionResponse{}
+	for _, part := range partitions {
+		filesystem := ""
+		if part.Type != 'E' {
+			filesystem = structures.DetectFilesystem(disk.Path, int64(part.DataStart()))
+		}
+
+		response = append(response, PartitionResponse{
+			Name:       part.Name,
+			Type:       partitionTypeName(part.Type),
+			Fit:        fitName(part.Fit),
+			Status:     string(part.Status),
+			Start:      part.Start,
+			End:        part.Start + part.Size,
+			Size:       part.Size,
+			MountID:    stores.GetMountedIDByName(disk.Path, part.Name),
+			Filesystem: filesystem,
+		})
+	}
+
+	return c.JSON(response)
+}
+
+func newDiskResponse(disk stores.DiskInfo, mbr *structures.MBR) DiskResponse {
+	table := "MBR"
+	if mbr.IsGPT() {
+		table = "GPT"
+	}
+
+	return DiskResponse{
+		Name:              disk.Name,
+		Path:              disk.Path,
+		Size:              mbr.Mbr_size,
+		Fit:               fitName(mbr.Mbr_disk_fit[0]),
+		Signature:         mbr.Mbr_disk_signature,
+		CreationDate:      time.Unix(int64(mbr.Mbr_creation_date), 0).Format(time.RFC3339),
+		Table:             table,
+		MountedPartitions: stores.GetMountedIDsByDisk(disk.Path),
+	}
+}
+
+// fitName convierte el byte de ajuste a su nombre (FF, BF, WF)
+func fitName(fit byte) string {
+	switch fit {
+	case 'F', 'B', 'W':
+		return fmt.Sprintf("%cF", fit)
+	default:
+		return string(fit)
+	}
+}
+
+// partitionTypeName convierte el tipo de partición a un nombre legible
+func partitionTypeName(typ byte) string {
+	switch typ {
+	case 'P':
+		return "primaria"
+	case 'E':
+		return "extendida"
+	case 'L':
+		return "lógica"
+	default:
+		return string(typ)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"path"
+	"time"
+
+	"backend/stores"
+	"backend/structures"
+	"backend/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ---------- ESTRUCTURAS ----------
+type DirEntryResponse struct {
+	Name        string `json:"name"`
+	Inode       int32  `json:"inode"`
+	Type        string `json:"type"`
+	Size        int32  `json:"size"`
+	Owner       string `json:"owner"`
+	Group       string `json:"group"`
+	Permissions string `json:"permissions"`
+	Atime       string `json:"atime"`
+	Ctime       string `json:"ctime"`
+	Mtime       string `json:"mtime"`
+}
+
+type DirectoryResponse struct {
+	Path    string             `json:"path"`
+	Inode   int32              `json:"inode"`
+	Entries []DirEntryResponse `json:"entries"`
+}
+
+// ---------- HANDLER: GET /partitions/:id/dir?path= ----------
+func handleDirectory(c *fiber.Ctx) error {
+	partitionID := c.Params("id")
+	dirPath := cleanFSPath(c.Query("path", "/"))
+
+	// Igual que al leer archivos, el listado depende del permiso de lectura del usuario de la sesión
+	session, err := requestSession(c)
+	if err != nil || !session.IsAuthenticated() {
+		return c.Status(fiber.StatusUnauthorized).SendString("Debe iniciar sesión para listar carpetas")
+	}
+	if session.GetPartitionID() != partitionID {
+		return c.Status(fiber.StatusForbidden).SendString("La sesión activa pertenece a otra partición")
+	}
+
+	unlock, err := stores.RLockPartition(partitionID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Partición no montada")
+	}
+	defer unlock()
+
+	sb, _, diskPath, err := stores.GetMountedPartitionSuperblock(partitionID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Partición no montada")
+	}
+
+	inodeIndex, err := structures.FindInodeByPath(diskPath, dirPath, *sb)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("La carpeta no existe: " + dirPath)
+	}
+
+	inode, err := sb.ReadInode(diskPath, inodeIndex)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer el inodo")
+	}
+	if inode.I_type[0] != '0' {
+		return c.Status(fiber.StatusBadRequest).SendString("La ruta no es una carpeta: " + dirPath)
+	}
+	if !utils.HasReadPermission(session, *inode) {
+		return c.Status(fiber.StatusForbidden).SendString("No tiene permiso de lectura en " + dirPath)
+	}
+
+	entries, err := sb.ListDirectory(diskPath, inode)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer la carpeta")
+	}
+
+	// Los nombres de dueño y grupo se resuelven desde users.txt
+	usersContent, err := sb.ReadUsersFile(diskPath)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer users.txt")
+	}
+	users := utils.ParseUsersFile(usersContent)
+
+	response := DirectoryResponse{Path: dirPath, Inode: inodeIndex, Entries: []DirEntryResponse{}}
+	for _, entry := range entries {
+		child, err := sb.ReadInode(diskPath, entry.Inode)
+		if err != nil {
+			continue
+		}
+		response.Entries = append(response.Entries, newDirEntryResponse(entry, child, users))
+	}
+
+	return c.JSON(response)
+}
+
+// ---------- HANDLER: GET /partitions/:id/file?path= ----------
+func handleFile(c *fiber.Ctx) error {
+	partitionID := c.Params("id")
+	filePath := cleanFSPath(c.Query("path"))
+
+	// Los permisos de lectura dependen del usuario de la sesión del token, que debe estar en esta partición
+	session, err := requestSession(c)
+	if err != nil || !session.IsAuthenticated() {
+		return c.Status(fiber.StatusUnauthorized).SendString("Debe iniciar sesión para leer archivos")
+	}
+	if session.GetPartitionID() != partitionID {
+		return c.Status(fiber.StatusForbidden).SendString("La sesión activa pertenece a otra partición")
+	}
+
+	unlock, err := stores.RLockPartition(partitionID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Partición no montada")
+	}
+	defer unlock()
+
+	sb, _, diskPath, err := stores.GetMountedPartitionSuperblock(partitionID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Partición no montada")
+	}
+
+	inodeIndex, err := structures.FindInodeByPath(diskPath, filePath, *sb)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("El archivo no existe: " + filePath)
+	}
+
+	inode, err := sb.ReadInode(diskPath, inodeIndex)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer el inodo")
+	}
+	if inode.I_type[0] != '1' {
+		return c.Status(fiber.StatusBadRequest).SendString("La ruta no es un archivo: " + filePath)
+	}
+	if !utils.HasReadPermission(session, *inode) {
+		return c.Status(fiber.StatusForbidden).SendString("No tiene permiso de lectura en " + filePath)
+	}
+
+	content, err := sb.ReadFile(diskPath, inode)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer el archivo")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/plain; charset=utf-8")
+	return c.SendStream(bytes.NewReader(content), len(content))
+}
+
+func newDirEntryResponse(entry structures.DirEntry, inode *structures.Inode, users *utils.UsersFile) DirEntryResponse {
+	entryType := "file"
+	if inode.I_type[0] == '0' {
+		entryType = "folder"
+	}
+
+	return DirEntryResponse{
+		Name:        entry.Name,
+		Inode:       entry.Inode,
+		Type:        entryType,
+		Size:        inode.I_size,
+		Owner:       users.UserName(int(inode.I_uid)),
+		Group:       users.GroupName(int(inode.I_gid)),
+		Permissions: utils.PermissionString(*inode),
+		Atime:       formatInodeTime(inode.I_atime),
+		Ctime:       formatInodeTime(inode.I_ctime),
+		Mtime:       formatInodeTime(inode.I_mtime),
+	}
+}
+
+// cleanFSPath normaliza una ruta dentro de la partición, siempre absoluta
+func cleanFSPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// formatInodeTime convierte una fecha de inodo a RFC3339
+func formatInodeTime(t float32) string {
+	return time.Unix(int64(t), 0).Format(time.RFC3339)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"path"
+
+	"backend/commands"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ---------- ESTRUCTURAS ----------
+type CreateFolderRequest struct {
+	Path    string `json:"path"`
+	Parents bool   `json:"parents"`
+}
+
+type RenameRequest struct {
+	Path    string `json:"path"`
+	NewName string `json:"new_name"`
+}
+
+type FSErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+type FSChangeResponse struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type UploadResponse struct {
+	Folder string          `json:"folder"`
+	Files  []string        `json:"files"`            // Archivos escritos
+	Failed []UploadFailure `json:"failed,omitempty"` // Archivos que no se pudieron escribir
+}
+
+type UploadFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// ---------- HANDLER: POST /partitions/:id/dir ----------
+func handleCreateFolder(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return fsError(c, fiber.StatusUnauthorized, "not_authenticated", err.Error())
+	}
+
+	var req CreateFolderRequest
+	if err := c.BodyParser(&req); err != nil || req.Path == "" {
+		return fsError(c, fiber.StatusBadRequest, "invalid_request", "Se requiere el campo path")
+	}
+
+	dirPath := cleanFSPath(req.Path)
+	if err := commands.CreateFolderAt(session, c.Params("id"), dirPath, req.Parents); err != nil {
+		return fsCommandError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(FSChangeResponse{Path: dirPath, Message: "Carpeta creada"})
+}
+
+// ---------- HANDLER: POST /partitions/:id/upload ----------
+// Recibe un multipart con el campo "path" (carpeta destino) y uno o más campos "file".
+// Cada archivo se escribe por separado: si alguno falla se siguen escribiendo los demás y
+// la respuesta (207) indica cuáles quedaron escritos y por qué fallaron los otros.
+func handleUpload(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return fsError(c, fiber.StatusUnauthorized, "not_authenticated", err.Error())
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return fsError(c, fiber.StatusBadRequest, "invalid_request", "Se esperaba un formulario multipart")
+	}
+
+	folder := "/"
+	if values := form.Value["path"]; len(values) > 0 {
+		folder = cleanFSPath(values[0])
+	}
+	overwrite := len(form.Value["overwrite"]) > 0 && form.Value["overwrite"][0] == "true"
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		return fsError(c, fiber.StatusBadRequest, "invalid_request", "Se requiere al menos un campo file")
+	}
+
+	response := UploadResponse{Folder: folder, Files: []string{}}
+	failedStatus := 0 // Código HTTP de la primera falla
+	fail := func(filePath string, status int, code string, message string) {
+		if failedStatus == 0 {
+			failedStatus = status
+		}
+		response.Failed = append(response.Failed, UploadFailure{Path: filePath, Error: message, Code: code})
+	}
+
+	for _, header := range files {
+		filePath := path.Join(folder, path.Base(header.Filename))
+
+		content, err := readUploadedFile(header)
+		if err != nil {
+			fail(filePath, fiber.StatusBadRequest, "invalid_request", "No se pudo leer "+header.Filename)
+			continue
+		}
+		if _, err := commands.WriteFileAt(session, c.Params("id"), filePath, content, overwrite); err != nil {
+			status, code := fsCommandStatus(err)
+			fail(filePath, status, code, err.Error())
+			continue
+		}
+		response.Files = append(response.Files, filePath)
+	}
+
+	switch {
+	case len(response.Failed) == 0:
+		return c.Status(fiber.StatusCreated).JSON(response)
+	case len(response.Files) == 0:
+		// Sin ningún archivo escrito la petición falla con el motivo del primero
+		return fsError(c, failedStatus, response.Failed[0].Code, response.Failed[0].Error)
+	default:
+		return c.Status(fiber.StatusMultiStatus).JSON(response)
+	}
+}
+
+// readUploadedFile lee el contenido de un archivo del formulario multipart
+func readUploadedFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// ---------- HANDLER: PUT /partitions/:id/file?path= ----------
+// Reemplaza el contenido del archivo con el cuerpo de la petición, o lo crea si no existe
+func handleWriteFile(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return fsError(c, fiber.StatusUnauthorized, "not_authenticated", err.Error())
+	}
+
+	if c.Query("path") == "" {
+		return fsError(c, fiber.StatusBadRequest, "invalid_request", "Se requiere el parámetro path")
+	}
+	filePath := cleanFSPath(c.Query("path"))
+
+	created, err := commands.WriteFileAt(session, c.Params("id"), filePath, c.Body(), true)
+	if err != nil {
+		return fsCommandError(c, err)
+	}
+
+	if created {
+		return c.Status(fiber.StatusCreated).JSON(FSChangeResponse{Path: filePath, Message: "Archivo creado"})
+	}
+	return c.JSON(FSChangeResponse{Path: filePath, Message: "Archivo actualizado"})
+}
+
+// ---------- HANDLER: PATCH /partitions/:id/entry ----------
+func handleRename(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return fsError(c, fiber.StatusUnauthorized, "not_authenticated", err.Error())
+	}
+
+	var req RenameRequest
+	if err := c.BodyParser(&req); err != nil || req.Path == "" || req.NewName == "" {
+		return fsError(c, fiber.StatusBadRequest, "invalid_request", "Se requieren los campos path y new_name")
+	}
+
+	entryPath := cleanFSPath(req.Path)
+	if err := commands.RenameAt(session, c.Params("id"), entryPath, req.NewName); err != nil {
+		return fsCommandError(c, err)
+	}
+
+	return c.JSON(FSChangeResponse{Path: path.Join(path.Dir(entryPath), req.NewName), Message: "Renombrado"})
+}
+
+// ---------- HANDLER: DELETE /partitions/:id/entry?path= ----------
+func handleDelete(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return fsError(c, fiber.StatusUnauthorized, "not_authenticated", err.Error())
+	}
+
+	if c.Query("path") == "" {
+		return fsError(c, fiber.StatusBadRequest, "invalid_request", "Se requiere el parámetro path")
+	}
+	entryPath := cleanFSPath(c.Query("path"))
+
+	if err := commands.RemoveAt(session, c.Params("id"), entryPath); err != nil {
+		return fsCommandError(c, err)
+	}
+
+	return c.JSON(FSChangeResponse{Path: entryPath, Message: "Eliminado"})
+}
+
+// fsCommandError traduce los errores de commands a un código HTTP
+func fsCommandError(c *fiber.Ctx, err error) error {
+	status, code := fsCommandStatus(err)
+	return fsError(c, status, code, err.Error())
+}
+
+// fsCommandStatus devuelve el código HTTP y el código de error de un error de commands
+func fsCommandStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, commands.ErrNotAuthenticated):
+		return fiber.StatusUnauthorized, "not_authenticated"
+	case errors.Is(err, commands.ErrPermissionDenied):
+		return fiber.StatusForbidden, "permission_denied"
+	case errors.Is(err, commands.ErrNotFound):
+		return fiber.StatusNotFound, "not_found"
+	case errors.Is(err, commands.ErrAlreadyExists):
+		return fiber.StatusConflict, "already_exists"
+	case errors.Is(err, commands.ErrInvalidPath):
+		return fiber.StatusBadRequest, "invalid_path"
+	default:
+		return fiber.StatusInternalServerError, "internal_error"
+	}
+}
+
+func fsError(c *fiber.Ctx, status int, code string, message string) error {
+	return c.Status(status).JSON(FSErrorResponse{Error: message, Code: code})
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"backend/commands"
+	"backend/stores"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ---------- HANDLER: GET /partitions/:id/reports/:name ----------
+// Devuelve en JSON los datos con los que se dibuja el reporte; los reportes file y ls
+// reciben la ruta dentro de la partición en ?path_file_ls=
+func handleReportData(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).SendString("Error: " + err.Error())
+	}
+
+	data, err := commands.ReportJSON(session, c.Params("id"), c.Params("name"), c.Query("path_file_ls"))
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return c.JSON(data)
+}
+
+// reportError responde con el código HTTP que corresponde al error de un reporte: los
+// parámetros inválidos son 400 y cualquier error no reconocido es de lectura del disco (500)
+func reportError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, commands.ErrNotAuthenticated):
+		status = fiber.StatusUnauthorized
+	case errors.Is(err, commands.ErrPermissionDenied):
+		status = fiber.StatusForbidden
+	case errors.Is(err, commands.ErrNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, commands.ErrInvalidReport):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).SendString("Error: " + err.Error())
+}
+
+// ---------- ESTRUCTURAS ----------
+type CreateReportRequest struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Format     string `json:"format"` // svg, png, json o txt; vacío usa el formato del reporte
+	PathFileLs string `json:"path_file_ls"`
+}
+
+// Tipo de contenido de cada extensión que puede tener un reporte
+var reportContentTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".svg":  "image/svg+xml",
+	".pdf":  "application/pdf",
+	".json": "application/json",
+	".dot":  "text/vnd.graphviz; charset=utf-8",
+}
+
+// ---------- HANDLER: GET /reports ----------
+// Lista los reportes generados en la partición de la sesión
+func handleReports(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).SendString("Error: " + err.Error())
+	}
+
+	partitionID := session.GetPartitionID()
+	list := []stores.ReportInfo{}
+	for _, report := range stores.ListReports() {
+		if report.PartitionID == partitionID {
+			list = append(list, report)
+		}
+	}
+	return c.JSON(list)
+}
+
+// ---------- HANDLER: GET /reports/:rid ----------
+// Devuelve el archivo del reporte; con ?download=true se descarga como adjunto. Solo se
+// pueden ver los reportes de la partición de la sesión.
+func handleReportFile(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).SendString("Error: " + err.Error())
+	}
+	report, ok := stores.GetReport(c.Params("rid"))
+	if !ok || report.PartitionID != session.GetPartitionID() {
+		return c.Status(fiber.StatusNotFound).SendString("Error: el reporte no existe")
+	}
+
+	file, err := os.Open(report.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c.Status(fiber.StatusNotFound).SendString("Error: el archivo del reporte ya no existe: " + report.Path)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Error: " + err.Error())
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return c.Status(fiber.StatusInternalServerError).SendString("Error: " + err.Error())
+	}
+
+	// Los reportes de texto (bm_inode, bm_block, file) se sirven como texto plano
+	contentType, ok := reportContentTypes[strings.ToLower(filepath.Ext(report.Path))]
+	if !ok {
+		contentType = "text/plain; charset=utf-8"
+	}
+	if c.QueryBool("download") {
+		c.Attachment(report.Path)
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+
+	// El archivo se cierra cuando termina de enviarse
+	return c.SendStream(file, int(info.Size()))
+}
+
+// ---------- HANDLER: POST /reports ----------
+// Genera un reporte de la partición de la sesión y devuelve su entrada en el registro.
+// El archivo se guarda en la carpeta de reportes del servidor, nunca en una ruta del cliente.
+func handleCreateReport(c *fiber.Ctx) error {
+	session, err := requestSession(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).SendString("Error: " + err.Error())
+	}
+
+	var req CreateReportRequest
+	if err := c.BodyParser(&req); err != nil || req.ID == "" || req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Error: se requieren los campos id y name")
+	}
+
+	report, err := commands.GenerateReport(session, req.ID, req.Name, req.Format, req.PathFileLs)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+
+	"backend/stores"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Cabecera alternativa a Authorization para enviar el token de sesión
+const sessionHeader = "X-Session-Token"
+
+// sessionToken obtiene el token de la petición: "Authorization: Bearer <token>" o X-Session-Token
+func sessionToken(c *fiber.Ctx) string {
+	if auth := c.Get(fiber.HeaderAuthorization); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return strings.TrimSpace(c.Get(sessionHeader))
+}
+
+// requestSession devuelve la sesión del token de la petición
+func requestSession(c *fiber.Ctx) (*stores.AuthStore, error) {
+	token := sessionToken(c)
+	if token == "" {
+		return nil, stores.ErrSessionNotFound
+	}
+	return stores.GetSession(token)
+}
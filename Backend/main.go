@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"backend/analyzer"
+	"backend/commands"
 	"backend/stores"
 	"backend/structures"
 
@@ -19,7 +22,19 @@ type CommandRequest struct {
 }
 
 type CommandResponse struct {
-	Output string `json:"output"`
+	Output  string          `json:"output"`            // Texto de todos los comandos, para la consola
+	Results []CommandResult `json:"results,omitempty"` // Resultado de cada comando
+	Token   string          `json:"token,omitempty"`   // Token de la sesión, si quedó autenticada
+}
+
+// CommandResult resultado de una línea del script
+type CommandResult struct {
+	Line    int    `json:"line"`    // Número de línea en la entrada, desde 1
+	Command string `json:"command"` // Nombre del comando, en minúsculas
+	Input   string `json:"input"`   // Línea tal como se escribió
+	Success bool   `json:"success"`
+	Message string `json:"message"`        // Salida del comando o mensaje de error
+	Data    any    `json:"data,omitempty"` // Datos del comando, ej: el ID que generó mount
 }
 
 type LoginRequest struct {
@@ -28,6 +43,14 @@ type LoginRequest struct {
 	PartitionID string `json:"partition_id"`
 }
 
+type LoginResponse struct {
+	Message     string `json:"message"`
+	Token       string `json:"token"`
+	Username    string `json:"username"`
+	PartitionID string `json:"partition_id"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
 // ---------- CONSTANTES ----------
 const (
 	errInvalidRequest  = "Error: Petición inválida"
@@ -36,6 +59,14 @@ const (
 
 // ---------- FUNCIÓN PRINCIPAL ----------
 func main() {
+	// Recuperar los discos y las particiones montadas antes del último reinicio
+	restored, discarded, err := commands.RestoreState()
+	if err != nil {
+		log.Println("No se pudo recuperar el estado de montaje:", err)
+	} else {
+		log.Printf("Estado recuperado de %s: %d montadas, %d descartadas\n", stores.StateFilePath(), len(restored), len(discarded))
+	}
+
 	app := fiber.New()
 
 	// Middleware CORS (permite conexión desde React)
@@ -44,7 +75,22 @@ func main() {
 	// Rutas disponibles
 	app.Post("/execute", handleExecute)
 	app.Post("/login", handleLogin)
+	app.Post("/logout", handleLogout)
+	app.Get("/commands", handleCommands)
 	app.Get("/filesystem/:id", handleFilesystem) // ✅ NUEVO endpoint
+	app.Get("/disks", handleDisks)
+	app.Get("/disks/:name/partitions", handleDiskPartitions)
+	app.Get("/partitions/:id/dir", handleDirectory)
+	app.Get("/partitions/:id/file", handleFile)
+	app.Post("/partitions/:id/dir", handleCreateFolder)
+	app.Post("/partitions/:id/upload", handleUpload)
+	app.Put("/partitions/:id/file", handleWriteFile)
+	app.Patch("/partitions/:id/entry", handleRename)
+	app.Delete("/partitions/:id/entry", handleDelete)
+	app.Get("/partitions/:id/reports/:name", handleReportData)
+	app.Get("/reports", handleReports)
+	app.Get("/reports/:rid", handleReportFile)
+	app.Post("/reports", handleCreateReport)
 
 	// Iniciar servidor
 	log.Println("Servidor iniciado en http://localhost:3001")
@@ -52,6 +98,8 @@ func main() {
 }
 
 // ---------- HANDLER: /execute ----------
+// Los comandos se ejecutan con la sesión del token; sin token se usa una sesión nueva
+// que solo se guarda si algún comando (login) la deja autenticada
 func handleExecute(c *fiber.Ctx) error {
 	var req CommandRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -60,24 +108,77 @@ func handleExecute(c *fiber.Ctx) error {
 		})
 	}
 
-	output := processCommands(req.Command)
-	return c.JSON(CommandResponse{Output: output})
+	session := stores.NewSession()
+	if token := sessionToken(c); token != "" {
+		existing, err := stores.GetSession(token)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(CommandResponse{
+				Output: "Error: " + err.Error(),
+			})
+		}
+		session = existing
+	}
+
+	results := processCommands(req.Command, session)
+	output := renderOutput(results)
+
+	token := session.Token()
+	switch {
+	case token == "" && session.IsAuthenticated():
+		newToken, err := stores.RegisterSession(session)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(CommandResponse{
+				Output: output + "Error: no se pudo crear la sesión\n",
+			})
+		}
+		token = newToken
+	case token != "" && !session.IsAuthenticated():
+		// El comando logout cerró la sesión del token
+		stores.EndSession(token)
+		token = ""
+	}
+
+	return c.JSON(CommandResponse{Output: output, Results: results, Token: token})
 }
 
-func processCommands(rawInput string) string {
+// processCommands ejecuta el script línea por línea; las líneas vacías y los comentarios
+// no generan resultado
+func processCommands(rawInput string, session *stores.AuthStore) []CommandResult {
 	lines := strings.Split(rawInput, "\n")
-	var outputBuilder strings.Builder
+	var results []CommandResult
 
-	for _, line := range lines {
+	for i, line := range lines {
 		cmd := strings.TrimSpace(line)
 		if cmd == "" {
 			continue
 		}
-		result, err := analyzer.Analyzer(cmd)
+		name, result, err := analyzer.Execute(cmd, session)
+		if name == "" && err == nil {
+			continue
+		}
+
+		entry := CommandResult{Line: i + 1, Command: name, Input: cmd, Success: err == nil}
 		if err != nil {
-			outputBuilder.WriteString(fmt.Sprintf("Error: %s\n", err.Error()))
+			entry.Message = err.Error()
 		} else {
-			outputBuilder.WriteString(fmt.Sprintf("%s\n", result))
+			entry.Message = result.Message
+			entry.Data = result.Data
+		}
+		results = append(results, entry)
+	}
+
+	return results
+}
+
+// renderOutput arma el texto de la consola con la salida de cada comando
+func renderOutput(results []CommandResult) string {
+	var outputBuilder strings.Builder
+
+	for _, result := range results {
+		if !result.Success {
+			outputBuilder.WriteString(fmt.Sprintf("Error: %s\n", result.Message))
+		} else {
+			outputBuilder.WriteString(fmt.Sprintf("%s\n", result.Message))
 		}
 	}
 
@@ -87,6 +188,12 @@ func processCommands(rawInput string) string {
 	return outputBuilder.String()
 }
 
+// ---------- HANDLER: /commands ----------
+// Devuelve los comandos con sus parámetros, para el autocompletado del frontend
+func handleCommands(c *fiber.Ctx) error {
+	return c.JSON(commands.ListCommands())
+}
+
 // ---------- HANDLER: /login ----------
 func handleLogin(c *fiber.Ctx) error {
 	var req LoginRequest
@@ -94,54 +201,61 @@ func handleLogin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).SendString("Error al leer datos de login")
 	}
 
-	partition, path, err := stores.GetMountedPartition(req.PartitionID)
-	if err != nil {
+	if _, _, err := stores.GetMountedPartition(req.PartitionID); err != nil {
 		return c.Status(fiber.StatusUnauthorized).SendString("Partición no montada")
 	}
 
-	var sb structures.SuperBlock
-	err = sb.Deserialize(path, int64(partition.Part_start))
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer SuperBlock")
+	session := stores.NewSession()
+	if err := commands.Login(session, req.Username, req.Password, req.PartitionID); err != nil {
+		if errors.Is(err, commands.ErrAccountLocked) {
+			return c.Status(fiber.StatusTooManyRequests).SendString("Error: " + err.Error())
+		}
+		return c.Status(fiber.StatusUnauthorized).SendString("Usuario o contraseña incorrectos")
 	}
 
-	block, err := sb.GetUsersBlock(path)
+	token, err := stores.RegisterSession(session)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer users.txt")
+		return c.Status(fiber.StatusInternalServerError).SendString("Error al crear la sesión")
 	}
 
-	content := string(block.B_content[:])
-	lines := strings.Split(content, "\n")
+	info := session.Info()
+	return c.JSON(LoginResponse{
+		Message:     "Login exitoso",
+		Token:       token,
+		Username:    info.Username,
+		PartitionID: info.PartitionID,
+		ExpiresAt:   session.ExpiresAt().Format(time.RFC3339),
+	})
+}
 
-	for _, line := range lines {
-		fields := strings.Split(line, ",")
-		if len(fields) < 5 || fields[1] != "U" {
-			continue
-		}
-		if fields[3] == req.Username && fields[4] == req.Password {
-			uid := 0
-			gid := 0
-			fmt.Sscanf(fields[0], "%d", &uid)
-			fmt.Sscanf(fields[2], "%d", &gid)
-			stores.Auth.Login(req.Username, req.Password, req.PartitionID, uid, gid)
-			return c.SendString("Login exitoso")
-		}
+// ---------- HANDLER: /logout ----------
+func handleLogout(c *fiber.Ctx) error {
+	token := sessionToken(c)
+	if _, err := stores.GetSession(token); err != nil {
+		return c.Status(fiber.StatusUnauthorized).SendString("No hay una sesión activa para cerrar")
 	}
 
-	return c.Status(fiber.StatusUnauthorized).SendString("Usuario o contraseña incorrectos")
+	stores.EndSession(token)
+	return c.SendString("Sesión cerrada exitosamente.")
 }
 
 // ---------- HANDLER: /filesystem/:id ----------
 func handleFilesystem(c *fiber.Ctx) error {
 	partitionID := c.Params("id")
 
+	unlock, err := stores.RLockPartition(partitionID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Partición no montada")
+	}
+	defer unlock()
+
 	partition, path, err := stores.GetMountedPartition(partitionID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).SendString("Partición no montada")
 	}
 
 	var sb structures.SuperBlock
-	if err := sb.Deserialize(path, int64(partition.Part_start)); err != nil {
+	if err := sb.Deserialize(path, int64(partition.Start)); err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Error al leer SuperBlock")
 	}
 
@@ -0,0 +1,349 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// dotGraph subconjunto del lenguaje DOT que usan los reportes: atributos del grafo y
+// de nodo por defecto, nodos con atributos (también por puerto, ej: Disco:f0) y aristas
+// entre nodos o puertos
+type dotGraph struct {
+	attrs     map[string]string
+	nodeAttrs map[string]string
+	nodes     []*dotNode
+	nodeIndex map[string]*dotNode
+	edges     []dotEdge
+}
+
+type dotNode struct {
+	id        string
+	attrs     map[string]string
+	portAttrs map[string]map[string]string
+}
+
+type dotEdge struct {
+	from, fromPort string
+	to, toPort     string
+}
+
+// attr devuelve el atributo del nodo o el valor por defecto del grafo
+func (g *dotGraph) attr(node *dotNode, name string) string {
+	if value, ok := node.attrs[name]; ok {
+		return value
+	}
+	return g.nodeAttrs[name]
+}
+
+// node devuelve el nodo con el ID indicado, creándolo la primera vez que se nombra
+func (g *dotGraph) node(id string) *dotNode {
+	if node, ok := g.nodeIndex[id]; ok {
+		return node
+	}
+	node := &dotNode{id: id, attrs: make(map[string]string), portAttrs: make(map[string]map[string]string)}
+	g.nodes = append(g.nodes, node)
+	g.nodeIndex[id] = node
+	return node
+}
+
+type dotTokenKind int
+
+const (
+	dotID     dotTokenKind = iota // identificador o número
+	dotString                     // "texto"
+	dotHTML                       // <etiqueta HTML>
+	dotPunct                      // { } [ ] ; , = : -> --
+)
+
+type dotToken struct {
+	kind  dotTokenKind
+	value string
+}
+
+// lexDot separa el archivo DOT en tokens
+func lexDot(input string) ([]dotToken, error) {
+	var tokens []dotToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					switch runes[i+1] {
+					case '"', '\\':
+						i++
+					default:
+						sb.WriteRune(runes[i])
+						i++
+					}
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("comillas sin cerrar")
+			}
+			i++
+			tokens = append(tokens, dotToken{dotString, sb.String()})
+		case r == '<':
+			depth := 0
+			start := i
+			for ; i < len(runes); i++ {
+				if runes[i] == '<' {
+					depth++
+				} else if runes[i] == '>' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("etiqueta HTML sin cerrar")
+			}
+			tokens = append(tokens, dotToken{dotHTML, string(runes[start+1 : i])})
+			i++
+		case r == '-' && i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '-'):
+			tokens = append(tokens, dotToken{dotPunct, string(runes[i : i+2])})
+			i += 2
+		case strings.ContainsRune("{}[];,=:", r):
+			tokens = append(tokens, dotToken{dotPunct, string(r)})
+			i++
+		case isDotIDRune(r):
+			start := i
+			for i < len(runes) && isDotIDRune(runes[i]) {
+				if runes[i] == '-' && i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '-') {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, dotToken{dotID, string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("carácter inesperado: %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+func isDotIDRune(r rune) bool {
+	return r == '_' || r == '.' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// dotParser analizador de la lista de tokens
+type dotParser struct {
+	tokens []dotToken
+	pos    int
+}
+
+func (p *dotParser) peek() (dotToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return dotToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *dotParser) next() (dotToken, error) {
+	token, ok := p.peek()
+	if !ok {
+		return dotToken{}, fmt.Errorf("fin inesperado del archivo")
+	}
+	p.pos++
+	return token, nil
+}
+
+// accept consume el signo indicado si es el siguiente token
+func (p *dotParser) accept(punct string) bool {
+	token, ok := p.peek()
+	if ok && token.kind == dotPunct && token.value == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *dotParser) expect(punct string) error {
+	if !p.accept(punct) {
+		token, _ := p.peek()
+		return fmt.Errorf("se esperaba '%s' y se encontró '%s'", punct, token.value)
+	}
+	return nil
+}
+
+// value lee un identificador, texto o etiqueta HTML
+func (p *dotParser) value() (string, error) {
+	token, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if token.kind == dotPunct {
+		return "", fmt.Errorf("se esperaba un valor y se encontró '%s'", token.value)
+	}
+	return token.value, nil
+}
+
+// parseDot interpreta el contenido de un archivo DOT generado por los reportes
+func parseDot(input string) (*dotGraph, error) {
+	tokens, err := lexDot(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{tokens: tokens}
+	g := &dotGraph{
+		attrs:     make(map[string]string),
+		nodeAttrs: make(map[string]string),
+		nodeIndex: make(map[string]*dotNode),
+	}
+
+	// Encabezado: [strict] digraph|graph [ID] {
+	token, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(token.value, "strict") {
+		if token, err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+	if kind := strings.ToLower(token.value); kind != "digraph" && kind != "graph" {
+		return nil, fmt.Errorf("se esperaba digraph y se encontró '%s'", token.value)
+	}
+	if token, ok := p.peek(); ok && token.kind != dotPunct {
+		p.pos++
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.accept("}") {
+			return g, nil
+		}
+		if p.accept(";") || p.accept(",") {
+			continue
+		}
+		if err := p.statement(g); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// statement interpreta una sentencia: atributo del grafo, atributos por defecto, nodo o arista
+func (p *dotParser) statement(g *dotGraph) error {
+	id, err := p.value()
+	if err != nil {
+		return err
+	}
+
+	// ID = valor
+	if p.accept("=") {
+		value, err := p.value()
+		if err != nil {
+			return err
+		}
+		g.attrs[id] = value
+		return nil
+	}
+
+	// node [...], edge [...], graph [...]
+	if token, ok := p.peek(); ok && token.kind == dotPunct && token.value == "[" {
+		switch strings.ToLower(id) {
+		case "node":
+			return p.attrList(g.nodeAttrs)
+		case "edge":
+			return p.attrList(make(map[string]string))
+		case "graph":
+			return p.attrList(g.attrs)
+		}
+	}
+
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+	node := g.node(id)
+
+	// Cadena de aristas: a -> b:p -> c
+	fromID, fromPort := id, port
+	isEdge := false
+	for p.accept("->") || p.accept("--") {
+		toID, err := p.value()
+		if err != nil {
+			return err
+		}
+		toPort, err := p.port()
+		if err != nil {
+			return err
+		}
+		g.node(toID)
+		g.edges = append(g.edges, dotEdge{from: fromID, fromPort: fromPort, to: toID, toPort: toPort})
+		fromID, fromPort = toID, toPort
+		isEdge = true
+	}
+
+	attrs := make(map[string]string)
+	if token, ok := p.peek(); ok && token.kind == dotPunct && token.value == "[" {
+		if err := p.attrList(attrs); err != nil {
+			return err
+		}
+	}
+	if isEdge {
+		return nil
+	}
+
+	target := node.attrs
+	if port != "" {
+		if node.portAttrs[port] == nil {
+			node.portAttrs[port] = make(map[string]string)
+		}
+		target = node.portAttrs[port]
+	}
+	for name, value := range attrs {
+		target[name] = value
+	}
+	return nil
+}
+
+// port lee el puerto opcional de un nodo (:puerto)
+func (p *dotParser) port() (string, error) {
+	if !p.accept(":") {
+		return "", nil
+	}
+	return p.value()
+}
+
+// attrList lee una lista [nombre=valor, ...] y la guarda en attrs
+func (p *dotParser) attrList(attrs map[string]string) error {
+	if err := p.expect("["); err != nil {
+		return err
+	}
+	for {
+		if p.accept("]") {
+			return nil
+		}
+		if p.accept(",") || p.accept(";") {
+			continue
+		}
+		name, err := p.value()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("="); err != nil {
+			return err
+		}
+		value, err := p.value()
+		if err != nil {
+			return err
+		}
+		attrs[name] = value
+	}
+}
@@ -0,0 +1,152 @@
+package reports
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// formatDot escribe el grafo otra vez como DOT, con todos los valores entre comillas
+func formatDot(g *dotGraph) string {
+	var sb strings.Builder
+	sb.WriteString("digraph G {\n")
+	sb.WriteString("graph " + formatAttrs(g.attrs) + ";\n")
+	sb.WriteString("node " + formatAttrs(g.nodeAttrs) + ";\n")
+	for _, node := range g.nodes {
+		sb.WriteString(quoteDot(node.id) + " " + formatAttrs(node.attrs) + ";\n")
+		ports := make([]string, 0, len(node.portAttrs))
+		for port := range node.portAttrs {
+			ports = append(ports, port)
+		}
+		sort.Strings(ports)
+		for _, port := range ports {
+			sb.WriteString(quoteDot(node.id) + ":" + quoteDot(port) + " " + formatAttrs(node.portAttrs[port]) + ";\n")
+		}
+	}
+	for _, edge := range g.edges {
+		from, to := quoteDot(edge.from), quoteDot(edge.to)
+		if edge.fromPort != "" {
+			from += ":" + quoteDot(edge.fromPort)
+		}
+		if edge.toPort != "" {
+			to += ":" + quoteDot(edge.toPort)
+		}
+		sb.WriteString(from + " -> " + to + ";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func formatAttrs(attrs map[string]string) string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, quoteDot(attrs[name])))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func quoteDot(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+func TestParseDot(t *testing.T) {
+	input := `digraph G {
+		rankdir=LR;
+		bgcolor="#f8f9fa"
+		node [shape=record, style="filled", fontname="Arial"];
+		// comentario
+		Disco[label="<f0> MBR|{<e0> Extendida|<ebr0> EBR}"];
+		Disco:f0 [fillcolor="#FF9999"];
+		t [label=<<table><tr><td port="p1">a &lt; b</td></tr></table>>];
+		a-b -> t:p1 -> Disco:e0 [color=red];
+		"con \"comillas\"" -- c;
+	}`
+
+	g, err := parseDot(input)
+	if err != nil {
+		t.Fatalf("parseDot: %v", err)
+	}
+
+	if g.attrs["rankdir"] != "LR" || g.attrs["bgcolor"] != "#f8f9fa" {
+		t.Errorf("atributos del grafo: %v", g.attrs)
+	}
+	if g.nodeAttrs["shape"] != "record" || g.nodeAttrs["fontname"] != "Arial" {
+		t.Errorf("atributos de nodo por defecto: %v", g.nodeAttrs)
+	}
+
+	var ids []string
+	for _, node := range g.nodes {
+		ids = append(ids, node.id)
+	}
+	if want := []string{"Disco", "t", "a-b", `con "comillas"`, "c"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("nodos = %q, se esperaba %q", ids, want)
+	}
+
+	disco := g.nodeIndex["Disco"]
+	if disco.attrs["label"] != "<f0> MBR|{<e0> Extendida|<ebr0> EBR}" {
+		t.Errorf("etiqueta de registro: %q", disco.attrs["label"])
+	}
+	if disco.portAttrs["f0"]["fillcolor"] != "#FF9999" {
+		t.Errorf("atributos del puerto f0: %v", disco.portAttrs["f0"])
+	}
+	if label := g.nodeIndex["t"].attrs["label"]; label != `<table><tr><td port="p1">a &lt; b</td></tr></table>` {
+		t.Errorf("etiqueta HTML: %q", label)
+	}
+
+	wantEdges := []dotEdge{
+		{from: "a-b", to: "t", toPort: "p1"},
+		{from: "t", fromPort: "p1", to: "Disco", toPort: "e0"},
+		{from: `con "comillas"`, to: "c"},
+	}
+	if !reflect.DeepEqual(g.edges, wantEdges) {
+		t.Errorf("aristas = %+v, se esperaba %+v", g.edges, wantEdges)
+	}
+	// Los atributos de una arista no se aplican a sus nodos
+	if _, ok := disco.attrs["color"]; ok {
+		t.Errorf("el atributo de la arista quedó en el nodo: %v", disco.attrs)
+	}
+}
+
+func TestParseDotErrors(t *testing.T) {
+	tests := map[string]string{
+		"sin digraph":         `G { a; }`,
+		"comillas sin cerrar": `digraph G { a [label="x]; }`,
+		"html sin cerrar":     `digraph G { a [label=<<b>x</b>]; }`,
+		"sin llave final":     `digraph G { a -> b;`,
+		"atributo sin valor":  `digraph G { a [label=]; }`,
+	}
+	for name, input := range tests {
+		if _, err := parseDot(input); err == nil {
+			t.Errorf("%s: se esperaba un error", name)
+		}
+	}
+}
+
+// TestParseDotRoundTrip interpreta el DOT de cada reporte, lo vuelve a escribir y
+// verifica que al interpretarlo otra vez se obtiene el mismo grafo
+func TestParseDotRoundTrip(t *testing.T) {
+	for name, dot := range sampleReportDots() {
+		first, err := parseDot(dot)
+		if err != nil {
+			t.Errorf("%s: parseDot: %v", name, err)
+			continue
+		}
+		second, err := parseDot(formatDot(first))
+		if err != nil {
+			t.Errorf("%s: parseDot del DOT reescrito: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s: el grafo cambió al reescribirlo", name)
+		}
+	}
+}
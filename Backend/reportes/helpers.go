@@ -0,0 +1,37 @@
+package reports
+
+import (
+	"strings"
+	"unicode"
+)
+
+// escapeHTML prepara un texto para una etiqueta HTML de Graphviz: escapa los caracteres
+// especiales, convierte los saltos de línea en <br/> y omite los caracteres no imprimibles
+func escapeHTML(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '&':
+			sb.WriteString("&amp;")
+		case r == '<':
+			sb.WriteString("&lt;")
+		case r == '>':
+			sb.WriteString("&gt;")
+		case r == '"':
+			sb.WriteString("&quot;")
+		case r == '\n':
+			sb.WriteString("<br/>")
+		case unicode.IsPrint(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// cString convierte un arreglo de bytes terminado en ceros a texto
+func cString(b []byte) string {
+	if i := strings.IndexByte(string(b), 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,75 @@
+package reports
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ImagePath devuelve la ruta donde queda la imagen de un reporte: las rutas .svg y las
+// de un servidor sin Graphviz se dibujan con el renderizador SVG propio, así que en ese
+// caso la extensión cambia a .svg
+func ImagePath(path string) string {
+	if !useNativeSVG(path) || strings.EqualFold(filepath.Ext(path), ".svg") {
+		return path
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".svg"
+}
+
+// Extensiones de imagen que puede pedir una ruta de reporte, con el formato de salida de
+// Graphviz (-T) que les corresponde
+var imageFormats = map[string]string{".svg": "svg", ".png": "png", ".jpg": "jpg", ".jpeg": "jpg", ".gif": "gif", ".pdf": "pdf"}
+
+// IsImagePath indica si la ruta tiene extensión de imagen
+func IsImagePath(path string) bool {
+	_, ok := imageFormats[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// useNativeSVG indica si la imagen se genera sin Graphviz: cuando se pide un .svg o
+// cuando el ejecutable dot no está instalado
+func useNativeSVG(path string) bool {
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		return true
+	}
+	_, err := exec.LookPath("dot")
+	return err != nil
+}
+
+// renderGraph genera la imagen del archivo DOT con Graphviz o, si corresponde, con el
+// renderizador SVG propio; devuelve la ruta de la imagen generada
+func renderGraph(dotFileName string, outputImage string, dotArgs ...string) (string, error) {
+	outputImage = ImagePath(outputImage)
+
+	if !useNativeSVG(outputImage) {
+		format, ok := imageFormats[strings.ToLower(filepath.Ext(outputImage))]
+		if !ok {
+			return "", fmt.Errorf("extensión de imagen no soportada: '%s', use .png, .jpg, .gif, .pdf o .svg", filepath.Ext(outputImage))
+		}
+		args := append([]string{"-T" + format}, dotArgs...)
+		args = append(args, dotFileName, "-o", outputImage)
+		if err := exec.Command("dot", args...).Run(); err != nil {
+			return "", fmt.Errorf("error al generar imagen con dot: %v", err)
+		}
+		return outputImage, nil
+	}
+
+	content, err := os.ReadFile(dotFileName)
+	if err != nil {
+		return "", fmt.Errorf("error al leer archivo DOT: %v", err)
+	}
+	graph, err := parseDot(string(content))
+	if err != nil {
+		return "", fmt.Errorf("error al interpretar archivo DOT: %v", err)
+	}
+	svg, err := renderSVG(graph)
+	if err != nil {
+		return "", fmt.Errorf("error al generar imagen SVG: %v", err)
+	}
+	if err := os.WriteFile(outputImage, []byte(svg), 0644); err != nil {
+		return "", fmt.Errorf("error al escribir imagen SVG: %v", err)
+	}
+	return outputImage, nil
+}
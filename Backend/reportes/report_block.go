@@ -0,0 +1,212 @@
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"backend/structures"
+)
+
+// BlockTableData datos del reporte block: los bloques en uso con su contenido
+type BlockTableData struct {
+	BlocksCount int32       `json:"blocks_count"`
+	Blocks      []BlockData `json:"blocks"`
+}
+
+// BlockData contenido de un bloque según su tipo: las entradas de una carpeta, el texto
+// de un archivo o los apuntadores de un bloque indirecto
+type BlockData struct {
+	Index    int32                `json:"index"`
+	Kind     structures.BlockKind `json:"kind"`
+	Entries  []FolderEntryData    `json:"entries,omitempty"`
+	Content  *string              `json:"content,omitempty"`
+	Pointers []int32              `json:"pointers,omitempty"`
+}
+
+type FolderEntryData struct {
+	Name  string `json:"name"`
+	Inode int32  `json:"inode"`
+}
+
+// readBlockData lee el bloque e interpreta su contenido según el tipo indicado
+func readBlockData(superblock *structures.SuperBlock, diskPath string, index int32, kind structures.BlockKind) (BlockData, error) {
+	offset := int64(superblock.S_block_start + index*superblock.S_block_size)
+	data := BlockData{Index: index, Kind: kind}
+
+	switch kind {
+	case structures.BlockFolder:
+		block := &structures.FolderBlock{}
+		if err := block.Deserialize(diskPath, offset); err != nil {
+			return data, fmt.Errorf("error al deserializar bloque %d: %v", index, err)
+		}
+		for _, content := range block.B_content {
+			data.Entries = append(data.Entries, FolderEntryData{Name: cString(content.B_name[:]), Inode: content.B_inodo})
+		}
+	case structures.BlockFile:
+		block := &structures.FileBlock{}
+		if err := block.Deserialize(diskPath, offset); err != nil {
+			return data, fmt.Errorf("error al deserializar bloque %d: %v", index, err)
+		}
+		content := cString(block.B_content[:])
+		data.Content = &content
+	case structures.BlockPointer:
+		block := &structures.PointerBlock{}
+		if err := block.Deserialize(diskPath, offset); err != nil {
+			return data, fmt.Errorf("error al deserializar bloque %d: %v", index, err)
+		}
+		data.Pointers = append([]int32(nil), block.P_pointers[:]...)
+	}
+	return data, nil
+}
+
+// BuildBlockData lee los bloques referenciados por los inodos en uso
+func BuildBlockData(superblock *structures.SuperBlock, diskPath string) (*BlockTableData, error) {
+	// Recorrer los inodos en uso y anotar el tipo de cada bloque que referencian
+	refs, err := usedBlockRefs(superblock, diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &BlockTableData{BlocksCount: superblock.S_blocks_count, Blocks: []BlockData{}}
+	for _, ref := range refs {
+		block, err := readBlockData(superblock, diskPath, ref.Index, ref.Kind)
+		if err != nil {
+			return nil, err
+		}
+		data.Blocks = append(data.Blocks, block)
+	}
+	return data, nil
+}
+
+// ReportBlock genera un reporte de los bloques utilizados, cada uno según su tipo real
+// (carpeta, archivo o apuntadores), enlazados en el orden en que fueron asignados
+func ReportBlock(superblock *structures.SuperBlock, diskPath string, path string) error {
+	data, err := BuildBlockData(superblock, diskPath)
+	if err != nil {
+		return err
+	}
+
+	outputImage, err := writeGraph(path, blockDot(data))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Imagen de bloques generada correctamente:", outputImage)
+	return nil
+}
+
+// blockDot dibuja cada bloque como una tabla, enlazados en orden
+func blockDot(data *BlockTableData) string {
+	var dotContent strings.Builder
+	dotContent.WriteString(`digraph G {
+		rankdir=LR;
+		node [shape=plaintext]
+	`)
+
+	for i, block := range data.Blocks {
+		dotContent.WriteString(fmt.Sprintf("block%d [label=<%s>];\n", block.Index, blockLabel(block)))
+
+		// Conexión al siguiente bloque
+		if i > 0 {
+			dotContent.WriteString(fmt.Sprintf("block%d -> block%d;\n", data.Blocks[i-1].Index, block.Index))
+		}
+	}
+
+	dotContent.WriteString("}")
+	return dotContent.String()
+}
+
+// blockLabel tabla del bloque según su tipo
+func blockLabel(block BlockData) string {
+	switch block.Kind {
+	case structures.BlockFolder:
+		return folderBlockLabel(block)
+	case structures.BlockPointer:
+		return pointerBlockLabel(block)
+	default:
+		return fileBlockLabel(block)
+	}
+}
+
+// usedBlockRefs devuelve los bloques referenciados por los inodos en uso, sin repetir y
+// ordenados por índice, que es el orden en que los asigna el bitmap
+func usedBlockRefs(superblock *structures.SuperBlock, diskPath string) ([]structures.BlockRef, error) {
+	bitmap, err := superblock.ReadBitmapInode(diskPath)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el bitmap de inodos: %v", err)
+	}
+
+	seen := make(map[int32]bool)
+	var refs []structures.BlockRef
+	for i, value := range bitmap {
+		if structures.IsBitmapFree(value) {
+			continue
+		}
+
+		inode, err := superblock.ReadInode(diskPath, int32(i))
+		if err != nil {
+			return nil, fmt.Errorf("error al deserializar inodo %d: %v", i, err)
+		}
+		inodeRefs, err := superblock.InodeBlockRefs(diskPath, inode)
+		if err != nil {
+			return nil, fmt.Errorf("error al recorrer los bloques del inodo %d: %v", i, err)
+		}
+
+		for _, ref := range inodeRefs {
+			if ref.Index < 0 || ref.Index >= superblock.S_blocks_count || seen[ref.Index] {
+				continue
+			}
+			seen[ref.Index] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Index < refs[j].Index })
+	return refs, nil
+}
+
+// folderBlockLabel tabla con las cuatro entradas nombre/inodo de un bloque de carpeta;
+// la celda del inodo de cada entrada tiene el puerto eN para enlazarla
+func folderBlockLabel(block BlockData) string {
+	var label strings.Builder
+	label.WriteString(`<table border="0" cellborder="1" cellspacing="0">`)
+	label.WriteString(fmt.Sprintf(`<tr><td colspan="2" bgcolor="#f9e79f"><b>Bloque Carpeta %d</b></td></tr>`, block.Index))
+	label.WriteString(`<tr><td><b>b_name</b></td><td><b>b_inodo</b></td></tr>`)
+	for i, entry := range block.Entries {
+		label.WriteString(fmt.Sprintf(`<tr><td>%s</td><td port="e%d">%d</td></tr>`,
+			escapeHTML(entry.Name), i, entry.Inode))
+	}
+	label.WriteString("</table>")
+	return label.String()
+}
+
+// fileBlockLabel tabla con el texto de un bloque de archivo
+func fileBlockLabel(block BlockData) string {
+	content := ""
+	if block.Content != nil {
+		content = *block.Content
+	}
+	return fmt.Sprintf(`<table border="0" cellborder="1" cellspacing="0">`+
+		`<tr><td bgcolor="#aed6f1"><b>Bloque Archivo %d</b></td></tr>`+
+		`<tr><td align="left">%s</td></tr>`+
+		`</table>`,
+		block.Index, escapeHTML(content))
+}
+
+// pointerBlockLabel tabla con los 16 apuntadores de un bloque de apuntadores; cada celda
+// tiene el puerto pN para enlazarla
+func pointerBlockLabel(block BlockData) string {
+	var label strings.Builder
+	label.WriteString(`<table border="0" cellborder="1" cellspacing="0">`)
+	label.WriteString(fmt.Sprintf(`<tr><td colspan="4" bgcolor="#d7bde2"><b>Bloque Apuntadores %d</b></td></tr>`, block.Index))
+	for row := 0; row+4 <= len(block.Pointers); row += 4 {
+		label.WriteString("<tr>")
+		for i, pointer := range block.Pointers[row : row+4] {
+			label.WriteString(fmt.Sprintf(`<td port="p%d">%d</td>`, row+i, pointer))
+		}
+		label.WriteString("</tr>")
+	}
+	label.WriteString("</table>")
+	return label.String()
+}
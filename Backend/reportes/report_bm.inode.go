@@ -1,72 +1,81 @@
 package reports
+
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"backend/structures"
-	"backend/utils"
 )
 
 // Códigos ANSI
 const (
-	Reset  = "\033[0m"
-	Red    = "\033[31m"
-	Green  = "\033[32m"
+	Reset = "\033[0m"
+	Red   = "\033[31m"
+	Green = "\033[32m"
 )
 
-func ReportBMInode(superblock *structures.SuperBlock, diskPath string, path string) error {
-	err := utils.CreateParentDirs(path)
-	if err != nil {
-		return err
-	}
+// BitmapData datos de los reportes bm_inode y bm_block: el bitmap normalizado a 0 (libre)
+// y 1 (ocupado) con sus totales
+type BitmapData struct {
+	Total  int    `json:"total"`
+	Used   int    `json:"used"`
+	Free   int    `json:"free"`
+	Bitmap string `json:"bitmap"`
+}
 
-	file, err := os.Open(diskPath)
-	if err != nil {
-		return fmt.Errorf("error al abrir el archivo de disco: %v", err)
+// newBitmapData normaliza los bytes del bitmap; los libres pueden ser '0', 'O' o 0
+func newBitmapData(bitmap []byte) *BitmapData {
+	var bits strings.Builder
+	data := &BitmapData{Total: len(bitmap)}
+	for _, value := range bitmap {
+		if structures.IsBitmapFree(value) {
+			bits.WriteByte('0')
+			data.Free++
+		} else {
+			bits.WriteByte('1')
+			data.Used++
+		}
 	}
-	defer file.Close()
+	data.Bitmap = bits.String()
+	return data
+}
 
-	totalInodes := superblock.S_inodes_count + superblock.S_free_inodes_count
+// bitmapText escribe el bitmap con 20 valores por línea, coloreados según su estado
+func bitmapText(data *BitmapData) string {
 	var bitmapContent strings.Builder
-
-	for i := int32(0); i < totalInodes; i++ {
-		_, err := file.Seek(int64(superblock.S_bm_inode_start+i), 0)
-		if err != nil {
-			return fmt.Errorf("error al establecer el puntero: %v", err)
-		}
-
-		char := make([]byte, 1)
-		_, err = file.Read(char)
-		if err != nil {
-			return fmt.Errorf("error al leer el byte: %v", err)
-		}
-
+	for i, bit := range data.Bitmap {
 		// Colorear dependiendo del valor
-		switch char[0] {
-		case '0':
+		if bit == '0' {
 			bitmapContent.WriteString(Green + "0" + Reset)
-		case '1':
+		} else {
 			bitmapContent.WriteString(Red + "1" + Reset)
-		default:
-			bitmapContent.WriteByte(char[0])
 		}
 
 		if (i+1)%20 == 0 {
 			bitmapContent.WriteString("\n")
 		}
 	}
+	return bitmapContent.String()
+}
 
-	// Escribir en archivo TXT
-	txtFile, err := os.Create(path)
+// BuildBMInodeData lee el bitmap de inodos
+func BuildBMInodeData(superblock *structures.SuperBlock, diskPath string) (*BitmapData, error) {
+	bitmap, err := superblock.ReadBitmapInode(diskPath)
 	if err != nil {
-		return fmt.Errorf("error al crear el archivo TXT: %v", err)
+		return nil, fmt.Errorf("error al leer el bitmap de inodos: %v", err)
 	}
-	defer txtFile.Close()
+	return newBitmapData(bitmap), nil
+}
 
-	_, err = txtFile.WriteString(bitmapContent.String())
+func ReportBMInode(superblock *structures.SuperBlock, diskPath string, path string) error {
+	data, err := BuildBMInodeData(superblock, diskPath)
 	if err != nil {
-		return fmt.Errorf("error al escribir en el archivo TXT: %v", err)
+		return err
+	}
+
+	// Escribir en archivo TXT
+	if err := writeText(path, bitmapText(data)); err != nil {
+		return err
 	}
 
 	fmt.Println("Archivo del bitmap de inodos generado:", path)
@@ -0,0 +1,33 @@
+package reports
+
+import (
+	"fmt"
+
+	"backend/structures"
+)
+
+// BuildBMBlockData lee el bitmap de bloques. CreateBitMaps marca los bloques libres con
+// 'O' (letra) y los asignadores escriben '1', así que cada byte se normaliza a 0 o 1.
+func BuildBMBlockData(superblock *structures.SuperBlock, diskPath string) (*BitmapData, error) {
+	bitmap, err := superblock.ReadBitmapBlock(diskPath)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el bitmap de bloques: %v", err)
+	}
+	return newBitmapData(bitmap), nil
+}
+
+// ReportBMBlock genera el reporte del bitmap de bloques, 20 bloques por línea
+func ReportBMBlock(superblock *structures.SuperBlock, diskPath string, path string) error {
+	data, err := BuildBMBlockData(superblock, diskPath)
+	if err != nil {
+		return err
+	}
+
+	// Escribir en archivo TXT
+	if err := writeText(path, bitmapText(data)); err != nil {
+		return err
+	}
+
+	fmt.Println("Archivo del bitmap de bloques generado:", path)
+	return nil
+}
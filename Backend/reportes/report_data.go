@@ -0,0 +1,105 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"backend/structures"
+	"backend/utils"
+)
+
+// Source partición de la que se generan los reportes
+type Source struct {
+	MBR            *structures.MBR
+	SuperBlock     *structures.SuperBlock
+	DiskPath       string
+	PartitionStart int32
+	PathFileLs     string // Ruta dentro de la partición para los reportes file y ls
+}
+
+// Data arma los datos del reporte indicado: los mismos con los que se dibuja la imagen
+// o se escribe el archivo de texto
+func Data(name string, src Source) (any, error) {
+	switch name {
+	case "mbr":
+		return BuildMBRData(src.MBR, src.DiskPath)
+	case "disk":
+		return BuildDiskData(src.MBR, src.DiskPath)
+	case "inode":
+		return BuildInodeData(src.SuperBlock, src.DiskPath)
+	case "block":
+		return BuildBlockData(src.SuperBlock, src.DiskPath)
+	case "bm_inode":
+		return BuildBMInodeData(src.SuperBlock, src.DiskPath)
+	case "bm_block":
+		return BuildBMBlockData(src.SuperBlock, src.DiskPath)
+	case "sb":
+		return BuildSBData(src.SuperBlock, src.PartitionStart), nil
+	case "file":
+		return BuildFileData(src.SuperBlock, src.DiskPath, src.PathFileLs)
+	case "ls":
+		return BuildLsData(src.SuperBlock, src.DiskPath, src.PathFileLs)
+	case "tree":
+		return BuildTreeData(src.SuperBlock, src.DiskPath)
+	case "journaling":
+		return BuildJournalingData(src.SuperBlock, src.PartitionStart, src.DiskPath)
+	}
+	return nil, fmt.Errorf("reporte desconocido: %s", name)
+}
+
+// IsJSONPath indica si el reporte se pide en JSON por la extensión de su ruta
+func IsJSONPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// WriteJSON guarda los datos de un reporte como JSON
+func WriteJSON(path string, data any) error {
+	if err := utils.CreateParentDirs(path); err != nil {
+		return err
+	}
+
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("error al convertir el reporte a JSON: %v", err)
+	}
+	if err := os.WriteFile(path, content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error al escribir el archivo JSON: %v", err)
+	}
+
+	fmt.Println("Reporte JSON generado:", path)
+	return nil
+}
+
+// writeGraph guarda el archivo DOT junto a la ruta del reporte y genera la imagen;
+// devuelve la ruta de la imagen generada
+func writeGraph(path string, dotContent string, dotArgs ...string) (string, error) {
+	if err := utils.CreateParentDirs(path); err != nil {
+		return "", err
+	}
+
+	dotFileName, outputImage := utils.GetFileNames(path)
+	if err := os.WriteFile(dotFileName, []byte(dotContent), 0644); err != nil {
+		return "", fmt.Errorf("error al escribir archivo DOT: %v", err)
+	}
+
+	// Generar la imagen con Graphviz, o con el renderizador SVG si no está instalado
+	return renderGraph(dotFileName, outputImage, dotArgs...)
+}
+
+// writeText guarda un reporte de texto
+func writeText(path string, content string) error {
+	if err := utils.CreateParentDirs(path); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("error al escribir en el archivo TXT: %v", err)
+	}
+	return nil
+}
@@ -3,129 +3,243 @@ package reports
 import (
 	structures "backend/structures"
 	utils "backend/utils"
+	"encoding/binary"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 )
 
-func ReportDISK(mbr *structures.MBR, reportPath string, diskPath string) error {
-	if err := utils.CreateParentDirs(reportPath); err != nil {
-		return err
-	}
+// DiskData datos del reporte disk: el disco dividido en segmentos consecutivos con el
+// porcentaje que ocupa cada uno
+type DiskData struct {
+	Size     int32         `json:"size"`
+	Table    string        `json:"table"` // MBR o GPT
+	Segments []DiskSegment `json:"segments"`
+}
+
+// DiskSegment área del disco; la extendida contiene sus EBRs, lógicas y espacio libre
+type DiskSegment struct {
+	Kind     string        `json:"kind"` // mbr, gpt, primary, extended, ebr, logical o free
+	Name     string        `json:"name,omitempty"`
+	Start    int64         `json:"start"`
+	Size     int64         `json:"size"`
+	Percent  float64       `json:"percent"`
+	Segments []DiskSegment `json:"segments,omitempty"`
+}
 
-	dotFileName, outputImage := utils.GetFileNames(reportPath)
+// BuildDiskData recorre el disco en orden y anota cada partición y cada espacio libre
+func BuildDiskData(mbr *structures.MBR, diskPath string) (*DiskData, error) {
 	totalSize := float64(mbr.Mbr_size)
-	lastByte := int64(utils.MBRSize())
+	segment := func(kind string, name string, start int64, end int64) DiskSegment {
+		return DiskSegment{Kind: kind, Name: name, Start: start, Size: end - start, Percent: float64(end-start) / totalSize * 100}
+	}
 
-	var dotContent strings.Builder
-	dotContent.WriteString(`digraph G {
-	rankdir=LR;
-	node [shape=record, style="filled", fontname="Arial"];
-	Disco[label="MBR`)
+	data := &DiskData{Size: mbr.Mbr_size, Table: "MBR"}
+	lastByte := int64(binary.Size(structures.MBR{}))
+	data.Segments = append(data.Segments, segment("mbr", "MBR", 0, lastByte))
 
 	var parts []utils.PartPos
-	colorLines := new(strings.Builder) // Aquí agregamos las líneas de color
+	if mbr.IsGPT() {
+		// En discos GPT las particiones están en la tabla GPT, que ocupa el inicio del disco
+		header, entries, err := structures.ReadGPT(diskPath)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo la tabla GPT: %v", err)
+		}
 
-	logicalCount := 0
-	freeCount := 0
-	primaryCount := 0
-	ebrCount := 0
+		data.Table = "GPT"
+		data.Segments = append(data.Segments, segment("gpt", "Encabezado y tabla GPT", lastByte, int64(header.FirstUsable)))
+		lastByte = int64(header.FirstUsable)
 
-	for _, part := range mbr.Mbr_partitions {
-		if part.Part_size > 0 {
-			name := strings.TrimRight(string(part.Part_name[:]), "\x00")
+		for _, entry := range entries {
+			if entry.IsFree() {
+				continue
+			}
 			parts = append(parts, utils.PartPos{
-				Start: int64(part.Part_start),
-				End:   int64(part.Part_start) + int64(part.Part_size),
-				Type:  part.Part_type[0],
-				Name:  name,
+				Start: int64(entry.PartStart),
+				End:   int64(entry.PartStart) + int64(entry.PartSize),
+				Type:  structures.GPTPartitionType,
+				Name:  entry.Name(),
 			})
 		}
+	} else {
+		for _, part := range mbr.Mbr_partitions {
+			if part.Part_size > 0 {
+				name := strings.TrimRight(string(part.Part_name[:]), "\x00")
+				parts = append(parts, utils.PartPos{
+					Start: int64(part.Part_start),
+					End:   int64(part.Part_start) + int64(part.Part_size),
+					Type:  part.Part_type[0],
+					Name:  name,
+				})
+			}
+		}
 	}
 	utils.SortPartsByStart(parts)
 
 	for _, p := range parts {
 		if p.Start > lastByte {
-			freePerc := ((float64(p.Start - lastByte)) / totalSize) * 100
-			dotContent.WriteString(fmt.Sprintf("|<f%d> Libre (%.2f%%)", freeCount, freePerc))
-			colorLines.WriteString(fmt.Sprintf("Disco:f%d [fillcolor=\"#DDDDDD\"];\n", freeCount))
-			freeCount++
+			data.Segments = append(data.Segments, segment("free", "", lastByte, p.Start))
 		}
 
-		partPerc := ((float64(p.End - p.Start)) / totalSize) * 100
-
 		if p.Type == 'E' {
-			dotContent.WriteString(fmt.Sprintf("|{ <e%d> Extendida", primaryCount))
-			colorLines.WriteString(fmt.Sprintf("Disco:e%d [fillcolor=\"#99CCFF\"];\n", primaryCount))
-
-			current := p.Start
-			for current < p.End && current != -1 {
-				ebr, err := structures.ReadEBR(diskPath, current)
-				if err != nil || ebr.PartSize <= 0 {
-					break
-				}
-
-				ebrEnd := int64(ebr.PartStart) + int64(ebr.PartSize)
-				ebrPerc := ((float64(ebrEnd - current)) / totalSize) * 100
+			extended := segment("extended", p.Name, p.Start, p.End)
+			extended.Segments = extendedSegments(diskPath, p.Start, p.End, segment)
+			data.Segments = append(data.Segments, extended)
+		} else {
+			data.Segments = append(data.Segments, segment("primary", p.Name, p.Start, p.End))
+		}
+		lastByte = p.End
+	}
 
-				dotContent.WriteString(fmt.Sprintf("|<ebr%d> EBR", ebrCount))
-				colorLines.WriteString(fmt.Sprintf("Disco:ebr%d [fillcolor=\"#FFFF99\"];\n", ebrCount))
+	if lastByte < int64(totalSize) {
+		data.Segments = append(data.Segments, segment("free", "", lastByte, int64(totalSize)))
+	}
 
-				dotContent.WriteString(fmt.Sprintf("|<log%d> Lógica (%.2f%%)", logicalCount, ebrPerc))
-				colorLines.WriteString(fmt.Sprintf("Disco:log%d [fillcolor=\"#99FF99\"];\n", logicalCount))
+	return data, nil
+}
 
-				if ebr.PartNext != -1 && ebrEnd < int64(ebr.PartNext) {
-					librePerc := ((float64(int64(ebr.PartNext)-ebrEnd)) / totalSize) * 100
-					dotContent.WriteString(fmt.Sprintf("|<flog%d> Libre (%.2f%%)", logicalCount, librePerc))
-					colorLines.WriteString(fmt.Sprintf("Disco:flog%d [fillcolor=\"#EEEEEE\"];\n", logicalCount))
-				}
+// extendedSegments recorre la cadena de EBRs de la extendida: cada EBR, su partición
+// lógica y el espacio libre entre ellas
+func extendedSegments(diskPath string, start int64, end int64, segment func(string, string, int64, int64) DiskSegment) []DiskSegment {
+	ebrSize := int64(binary.Size(structures.EBR{}))
+	segments := []DiskSegment{}
+	visited := make(map[int64]bool)
+	lastByte := start
+
+	current := start
+	for current >= start && current < end && !visited[current] {
+		visited[current] = true
+		ebr, err := structures.ReadEBR(diskPath, current)
+		if err != nil {
+			break
+		}
 
-				if ebr.PartNext == -1 {
-					break
-				}
-				current = int64(ebr.PartNext)
-				logicalCount++
-				ebrCount++
-			}
-			dotContent.WriteString("}")
-		} else {
-			dotContent.WriteString(fmt.Sprintf("|<p%d> Primaria (%.2f%%)", primaryCount, partPerc))
-			colorLines.WriteString(fmt.Sprintf("Disco:p%d [fillcolor=\"#FF9999\"];\n", primaryCount))
+		if current > lastByte {
+			segments = append(segments, segment("free", "", lastByte, current))
+		}
+		segments = append(segments, segment("ebr", "EBR", current, current+ebrSize))
+		lastByte = current + ebrSize
+
+		if ebr.PartSize > 0 {
+			logicalEnd := int64(ebr.PartStart) + int64(ebr.PartSize)
+			name := strings.TrimRight(string(ebr.PartName[:]), "\x00")
+			segments = append(segments, segment("logical", name, lastByte, logicalEnd))
+			lastByte = logicalEnd
 		}
 
-		lastByte = p.End
-		primaryCount++
+		if ebr.PartNext == -1 {
+			break
+		}
+		current = int64(ebr.PartNext)
 	}
 
-	if lastByte < int64(totalSize) {
-		finalLibrePerc := ((totalSize - float64(lastByte)) / totalSize) * 100
-		dotContent.WriteString(fmt.Sprintf("|<f%d> Libre (%.2f%%)", freeCount, finalLibrePerc))
-		colorLines.WriteString(fmt.Sprintf("Disco:f%d [fillcolor=\"#DDDDDD\"];\n", freeCount))
+	if lastByte < end {
+		segments = append(segments, segment("free", "", lastByte, end))
 	}
+	return segments
+}
 
-	// Cierra el nodo
-	dotContent.WriteString(`"];
-`)
-	dotContent.WriteString(colorLines.String())
-	dotContent.WriteString("}")
+func ReportDISK(mbr *structures.MBR, reportPath string, diskPath string) error {
+	data, err := BuildDiskData(mbr, diskPath)
+	if err != nil {
+		return err
+	}
 
-	// Escribir archivo .dot
-	file, err := os.Create(dotFileName)
+	outputImage, err := writeGraph(reportPath, diskDot(data))
 	if err != nil {
-		return fmt.Errorf("error creando DOT: %v", err)
+		return err
 	}
-	defer file.Close()
 
-	if _, err := file.WriteString(dotContent.String()); err != nil {
-		return fmt.Errorf("error escribiendo DOT: %v", err)
+	fmt.Println("Reporte DISK generado exitosamente en:", outputImage)
+	return nil
+}
+
+// Color de cada tipo de segmento en el reporte disk
+var diskColors = map[string]string{
+	"gpt":      "#FFCC99",
+	"primary":  "#FF9999",
+	"extended": "#99CCFF",
+	"ebr":      "#FFFF99",
+	"logical":  "#99FF99",
+	"free":     "#DDDDDD",
+}
+
+// diskDot dibuja el disco como un registro con un campo por segmento; la extendida es un
+// grupo con sus EBRs y lógicas
+func diskDot(data *DiskData) string {
+	var dotContent strings.Builder
+	dotContent.WriteString(`digraph G {
+	rankdir=LR;
+	node [shape=record, style="filled", fontname="Arial"];
+	Disco[label="`)
+
+	colorLines := new(strings.Builder) // Aquí agregamos las líneas de color
+	counts := make(map[string]int)
+
+	// field agrega un campo con su puerto y el color de su tipo
+	field := func(prefix string, kind string, text string) {
+		port := fmt.Sprintf("%s%d", prefix, counts[prefix])
+		counts[prefix]++
+		dotContent.WriteString(fmt.Sprintf("<%s> %s", port, text))
+
+		color := diskColors[kind]
+		if prefix == "flog" {
+			color = "#EEEEEE"
+		}
+		colorLines.WriteString(fmt.Sprintf("Disco:%s [fillcolor=\"%s\"];\n", port, color))
 	}
 
-	cmd := exec.Command("dot", "-Tpng", dotFileName, "-o", outputImage)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("error Graphviz: %v", err)
+	for i, seg := range data.Segments {
+		if i > 0 {
+			dotContent.WriteString("|")
+		}
+
+		switch seg.Kind {
+		case "mbr":
+			dotContent.WriteString("MBR")
+		case "gpt":
+			field("gpt", seg.Kind, fmt.Sprintf("Encabezado y tabla GPT (%.2f%%)", seg.Percent))
+		case "free":
+			field("f", seg.Kind, fmt.Sprintf("Libre (%.2f%%)", seg.Percent))
+		case "extended":
+			dotContent.WriteString("{ ")
+			field("e", seg.Kind, "Extendida")
+			for _, child := range seg.Segments {
+				dotContent.WriteString("|")
+				switch child.Kind {
+				case "ebr":
+					field("ebr", child.Kind, "EBR")
+				case "logical":
+					field("log", child.Kind, fmt.Sprintf("Lógica (%.2f%%)", child.Percent))
+				default:
+					field("flog", child.Kind, fmt.Sprintf("Libre (%.2f%%)", child.Percent))
+				}
+			}
+			dotContent.WriteString("}")
+		default:
+			text := fmt.Sprintf("Primaria (%.2f%%)", seg.Percent)
+			if data.Table == "GPT" {
+				text = fmt.Sprintf("%s (%.2f%%)", escapeRecord(seg.Name), seg.Percent)
+			}
+			field("p", seg.Kind, text)
+		}
 	}
 
-	fmt.Println("Reporte DISK generado exitosamente en:", outputImage)
-	return nil
+	// Cierra el nodo
+	dotContent.WriteString(`"];
+`)
+	dotContent.WriteString(colorLines.String())
+	dotContent.WriteString("}")
+	return dotContent.String()
+}
+
+// escapeRecord escapa los caracteres especiales de una etiqueta de registro
+func escapeRecord(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(`{}|<>"\`, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
 }
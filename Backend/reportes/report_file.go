@@ -0,0 +1,66 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	"backend/structures"
+)
+
+// FileData datos del reporte file: la ruta y el contenido completo del archivo
+type FileData struct {
+	Path    string `json:"path"`
+	Size    int    `json:"size"`
+	Content string `json:"content"`
+}
+
+// BuildFileData busca el archivo en la partición y lee todos sus bloques, directos e
+// indirectos, hasta I_size
+func BuildFileData(superblock *structures.SuperBlock, diskPath string, filePath string) (*FileData, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("el reporte file requiere el parámetro -path_file_ls")
+	}
+
+	// Buscar el archivo dentro de la partición
+	inodeIndex, err := structures.FindInodeByPath(diskPath, filePath, *superblock)
+	if err != nil {
+		return nil, fmt.Errorf("no se encontró el archivo %s: %v", filePath, err)
+	}
+	inode, err := superblock.ReadInode(diskPath, inodeIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error al deserializar inodo %d: %v", inodeIndex, err)
+	}
+	if inode.I_type[0] != '1' {
+		return nil, fmt.Errorf("la ruta %s no es un archivo", filePath)
+	}
+
+	content, err := superblock.ReadFile(diskPath, inode)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el archivo %s: %v", filePath, err)
+	}
+	return &FileData{Path: filePath, Size: len(content), Content: string(content)}, nil
+}
+
+// ReportFile exporta el contenido de un archivo de la partición a un archivo de texto,
+// con un encabezado que indica la ruta de origen
+func ReportFile(superblock *structures.SuperBlock, diskPath string, path string, filePath string) error {
+	data, err := BuildFileData(superblock, diskPath, filePath)
+	if err != nil {
+		return err
+	}
+
+	var report strings.Builder
+	report.WriteString("========================== FILE ===============================\n")
+	report.WriteString(fmt.Sprintf("Archivo: %s\n", data.Path))
+	report.WriteString(fmt.Sprintf("Tamaño : %d bytes\n", data.Size))
+	report.WriteString("=================================================================\n")
+	report.WriteString(data.Content)
+
+	// Escribir en archivo TXT
+	if err := writeText(path, report.String()); err != nil {
+		return err
+	}
+
+	fmt.Println("Reporte FILE generado:", path)
+	return nil
+}
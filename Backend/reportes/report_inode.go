@@ -2,70 +2,95 @@ package reports
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
-	"time"
+	"strings"
 
 	"backend/structures"
-	"backend/utils"
 )
 
-// ReportInode genera un reporte de los inodos utilizados y lo guarda en la ruta especificada
-func ReportInode(superblock *structures.SuperBlock, diskPath string, path string) error {
-	// Crear carpetas destino si no existen
-	err := utils.CreateParentDirs(path)
-	if err != nil {
-		return err
-	}
+// InodeTableData datos del reporte inode: los inodos en uso según el bitmap
+type InodeTableData struct {
+	InodesCount int32       `json:"inodes_count"`
+	Inodes      []InodeData `json:"inodes"`
+}
 
-	// Obtener nombres de archivo DOT y de imagen
-	dotFileName, outputImage := utils.GetFileNames(path)
+// InodeData atributos de un inodo y su arreglo I_block
+type InodeData struct {
+	Index  int32   `json:"index"`
+	UID    int32   `json:"uid"`
+	GID    int32   `json:"gid"`
+	Size   int32   `json:"size"`
+	Atime  string  `json:"atime"`
+	Ctime  string  `json:"ctime"`
+	Mtime  string  `json:"mtime"`
+	Type   string  `json:"type"` // 0 carpeta, 1 archivo
+	Perm   string  `json:"perm"`
+	Blocks []int32 `json:"blocks"`
+}
 
-	// Abrir disco para leer el bitmap
-	file, err := os.Open(diskPath)
-	if err != nil {
-		return fmt.Errorf("error al abrir el disco: %v", err)
+// newInodeData copia los atributos del inodo
+func newInodeData(index int32, inode *structures.Inode) InodeData {
+	return InodeData{
+		Index:  index,
+		UID:    inode.I_uid,
+		GID:    inode.I_gid,
+		Size:   inode.I_size,
+		Atime:  formatReportDate(inode.I_atime),
+		Ctime:  formatReportDate(inode.I_ctime),
+		Mtime:  formatReportDate(inode.I_mtime),
+		Type:   string(inode.I_type[0]),
+		Perm:   string(inode.I_perm[:]),
+		Blocks: append([]int32(nil), inode.I_block[:]...),
 	}
-	defer file.Close()
-
-	// Iniciar contenido del archivo DOT
-	dotContent := `digraph G {
-		node [shape=plaintext]
-	`
-
-	inodoIndex := 0
+}
 
-	for i := int32(0); i < superblock.S_inodes_count; i++ {
-		// Leer bit del bitmap de inodos
-		bitmapByte := make([]byte, 1)
-		_, err := file.Seek(int64(superblock.S_bm_inode_start+i), 0)
-		if err != nil {
-			return fmt.Errorf("error al acceder al bitmap de inodos: %v", err)
-		}
-		_, err = file.Read(bitmapByte)
-		if err != nil {
-			return fmt.Errorf("error al leer el bitmap de inodos: %v", err)
-		}
+// BuildInodeData lee los inodos marcados como usados en el bitmap de inodos
+func BuildInodeData(superblock *structures.SuperBlock, diskPath string) (*InodeTableData, error) {
+	bitmap, err := superblock.ReadBitmapInode(diskPath)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el bitmap de inodos: %v", err)
+	}
 
+	data := &InodeTableData{InodesCount: superblock.S_inodes_count, Inodes: []InodeData{}}
+	for i, value := range bitmap {
 		// Si el inodo no está en uso, saltarlo
-		if bitmapByte[0] != '1' {
+		if structures.IsBitmapFree(value) {
 			continue
 		}
 
-		// Leer estructura del inodo
-		inode := &structures.Inode{}
-		err = inode.Deserialize(diskPath, int64(superblock.S_inode_start+(i*superblock.S_inode_size)))
+		inode, err := superblock.ReadInode(diskPath, int32(i))
 		if err != nil {
-			return fmt.Errorf("error al deserializar inodo %d: %v", i, err)
+			return nil, fmt.Errorf("error al deserializar inodo %d: %v", i, err)
 		}
+		data.Inodes = append(data.Inodes, newInodeData(int32(i), inode))
+	}
+	return data, nil
+}
 
-		// Formatear fechas
-		atime := time.Unix(int64(inode.I_atime), 0).Format(time.RFC3339)
-		ctime := time.Unix(int64(inode.I_ctime), 0).Format(time.RFC3339)
-		mtime := time.Unix(int64(inode.I_mtime), 0).Format(time.RFC3339)
+// ReportInode genera un reporte de los inodos utilizados y lo guarda en la ruta especificada
+func ReportInode(superblock *structures.SuperBlock, diskPath string, path string) error {
+	data, err := BuildInodeData(superblock, diskPath)
+	if err != nil {
+		return err
+	}
+
+	outputImage, err := writeGraph(path, inodeDot(data))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Imagen de inodos generada correctamente:", outputImage)
+	return nil
+}
 
-		// Crear nodo DOT del inodo
-		dotContent += fmt.Sprintf(`inode%d [label=<
+// inodeDot dibuja cada inodo como una tabla, enlazados en orden
+func inodeDot(data *InodeTableData) string {
+	var dotContent strings.Builder
+	dotContent.WriteString(`digraph G {
+		node [shape=plaintext]
+	`)
+
+	for i, inode := range data.Inodes {
+		dotContent.WriteString(fmt.Sprintf(`inode%d [label=<
 			<table border="0" cellborder="1" cellspacing="0">
 				<tr><td colspan="2"><b>INODO %d</b></td></tr>
 				<tr><td>UID</td><td>%d</td></tr>
@@ -74,22 +99,22 @@ func ReportInode(superblock *structures.SuperBlock, diskPath string, path string
 				<tr><td>Atime</td><td>%s</td></tr>
 				<tr><td>Ctime</td><td>%s</td></tr>
 				<tr><td>Mtime</td><td>%s</td></tr>
-				<tr><td>Tipo</td><td>%c</td></tr>
+				<tr><td>Tipo</td><td>%s</td></tr>
 				<tr><td>Perm</td><td>%s</td></tr>
 				<tr><td colspan="2"><b>Bloques Directos</b></td></tr>`,
-			inodoIndex, inodoIndex,
-			inode.I_uid, inode.I_gid, inode.I_size,
-			atime, ctime, mtime,
-			rune(inode.I_type[0]),
-			string(inode.I_perm[:]))
+			inode.Index, inode.Index,
+			inode.UID, inode.GID, inode.Size,
+			inode.Atime, inode.Ctime, inode.Mtime,
+			escapeHTML(inode.Type),
+			escapeHTML(inode.Perm)))
 
 		// Bloques directos (0-11)
-		for j := 0; j <= 11; j++ {
-			dotContent += fmt.Sprintf("<tr><td>%d</td><td>%d</td></tr>", j, inode.I_block[j])
+		for j := 0; j < structures.DirectBlocks; j++ {
+			dotContent.WriteString(fmt.Sprintf("<tr><td>%d</td><td>%d</td></tr>", j, inode.Blocks[j]))
 		}
 
 		// Bloques indirectos (12, 13, 14)
-		dotContent += fmt.Sprintf(`
+		dotContent.WriteString(fmt.Sprintf(`
 			<tr><td colspan="2"><b>Bloque Indirecto</b></td></tr>
 			<tr><td>12</td><td>%d</td></tr>
 			<tr><td colspan="2"><b>Bloque Indirecto Doble</b></td></tr>
@@ -97,36 +122,14 @@ func ReportInode(superblock *structures.SuperBlock, diskPath string, path string
 			<tr><td colspan="2"><b>Bloque Indirecto Triple</b></td></tr>
 			<tr><td>14</td><td>%d</td></tr>
 			</table>>];
-		`, inode.I_block[12], inode.I_block[13], inode.I_block[14])
+		`, inode.Blocks[12], inode.Blocks[13], inode.Blocks[14]))
 
 		// Conexión al siguiente inodo
-		if inodoIndex > 0 {
-			dotContent += fmt.Sprintf("inode%d -> inode%d;\n", inodoIndex-1, inodoIndex)
+		if i > 0 {
+			dotContent.WriteString(fmt.Sprintf("inode%d -> inode%d;\n", data.Inodes[i-1].Index, inode.Index))
 		}
-		inodoIndex++
 	}
 
-	dotContent += "}"
-
-	// Crear archivo .dot
-	dotFile, err := os.Create(dotFileName)
-	if err != nil {
-		return fmt.Errorf("error al crear archivo DOT: %v", err)
-	}
-	defer dotFile.Close()
-
-	_, err = dotFile.WriteString(dotContent)
-	if err != nil {
-		return fmt.Errorf("error al escribir archivo DOT: %v", err)
-	}
-
-	// Ejecutar Graphviz para generar imagen
-	cmd := exec.Command("dot", "-Tpng", dotFileName, "-o", outputImage)
-	err = cmd.Run()
-	if err != nil {
-		return fmt.Errorf("error al generar imagen con dot: %v", err)
-	}
-
-	fmt.Println("Imagen de inodos generada correctamente:", outputImage)
-	return nil
+	dotContent.WriteString("}")
+	return dotContent.String()
 }
@@ -0,0 +1,90 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	"backend/structures"
+)
+
+// JournalingData datos del reporte journaling: las operaciones del journal en el orden
+// en que se realizaron
+type JournalingData struct {
+	Entries []JournalEntry `json:"entries"`
+}
+
+type JournalEntry struct {
+	Count     int32  `json:"count"`
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+	Content   string `json:"content"`
+	Date      string `json:"date"`
+}
+
+// BuildJournalingData lee el journal; solo existe en las particiones EXT3
+func BuildJournalingData(superblock *structures.SuperBlock, partitionStart int32, diskPath string) (*JournalingData, error) {
+	entries, err := superblock.ReadJournal(diskPath, partitionStart)
+	if err != nil {
+		return nil, fmt.Errorf("no se puede generar el reporte journaling: %v", err)
+	}
+
+	data := &JournalingData{Entries: []JournalEntry{}}
+	for _, entry := range entries {
+		data.Entries = append(data.Entries, JournalEntry{
+			Count:     entry.J_count,
+			Operation: entry.Operation(),
+			Path:      entry.Path(),
+			Content:   entry.Content(),
+			Date:      formatReportDate(entry.J_content.I_date),
+		})
+	}
+	return data, nil
+}
+
+// ReportJournaling genera la tabla de operaciones registradas en el journal de una
+// partición EXT3, en el orden en que se realizaron
+func ReportJournaling(superblock *structures.SuperBlock, partitionStart int32, diskPath string, path string) error {
+	// El journal se verifica antes de crear cualquier archivo
+	data, err := BuildJournalingData(superblock, partitionStart, diskPath)
+	if err != nil {
+		return err
+	}
+
+	outputImage, err := writeGraph(path, journalingDot(data))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Reporte JOURNALING generado:", outputImage)
+	return nil
+}
+
+// journalingDot dibuja la tabla de operaciones
+func journalingDot(data *JournalingData) string {
+	var dotContent strings.Builder
+	dotContent.WriteString(`digraph G {
+	bgcolor="#f8f9fa"
+	node [shape=plaintext fontname="Arial"]
+	tabla [label=<
+	<table border="0" cellborder="1" cellspacing="0" cellpadding="6" bgcolor="white">
+	<tr><td colspan="5" bgcolor="#2c3e50" align="center"><font color="white"><b>REPORTE JOURNALING</b></font></td></tr>
+	<tr><td bgcolor="#3498db"><font color="white">#</font></td><td bgcolor="#3498db"><font color="white">Operación</font></td><td bgcolor="#3498db"><font color="white">Path</font></td><td bgcolor="#3498db"><font color="white">Contenido</font></td><td bgcolor="#3498db"><font color="white">Fecha</font></td></tr>
+	`)
+
+	for i, entry := range data.Entries {
+		dotContent.WriteString(fmt.Sprintf(`<tr><td>%d</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>
+	`,
+			i+1,
+			escapeHTML(entry.Operation),
+			escapeHTML(entry.Path),
+			escapeHTML(entry.Content),
+			entry.Date))
+	}
+
+	if len(data.Entries) == 0 {
+		dotContent.WriteString(`<tr><td colspan="5" align="center" bgcolor="#f8f9fa"><i>El journal no tiene operaciones</i></td></tr>`)
+	}
+
+	dotContent.WriteString("</table>>]; }")
+	return dotContent.String()
+}
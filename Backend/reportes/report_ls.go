@@ -0,0 +1,138 @@
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"backend/structures"
+	"backend/utils"
+)
+
+// LsData datos del reporte ls: las entradas de una carpeta ordenadas por nombre
+type LsData struct {
+	Path    string    `json:"path"`
+	Entries []LsEntry `json:"entries"`
+}
+
+type LsEntry struct {
+	Name        string `json:"name"`
+	Inode       int32  `json:"inode"`
+	Type        string `json:"type"` // Carpeta o Archivo
+	Permissions string `json:"permissions"`
+	Owner       string `json:"owner"`
+	Group       string `json:"group"`
+	Size        int32  `json:"size"`
+	Created     string `json:"created"`
+	Modified    string `json:"modified"`
+}
+
+// BuildLsData lista una carpeta de la partición. Lee los inodos directamente, sin pasar
+// por la sesión, para que root pueda auditar cualquier carpeta.
+func BuildLsData(superblock *structures.SuperBlock, diskPath string, folderPath string) (*LsData, error) {
+	if folderPath == "" {
+		return nil, fmt.Errorf("el reporte ls requiere el parámetro -path_file_ls")
+	}
+
+	// Buscar la carpeta dentro de la partición
+	inodeIndex, err := structures.FindInodeByPath(diskPath, folderPath, *superblock)
+	if err != nil {
+		return nil, fmt.Errorf("no se encontró la carpeta %s: %v", folderPath, err)
+	}
+	folder, err := superblock.ReadInode(diskPath, inodeIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error al deserializar inodo %d: %v", inodeIndex, err)
+	}
+	if folder.I_type[0] != '0' {
+		return nil, fmt.Errorf("la ruta %s no es una carpeta", folderPath)
+	}
+
+	entries, err := superblock.ListDirectory(diskPath, folder)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la carpeta %s: %v", folderPath, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	// Nombres de usuarios y grupos
+	usersContent, err := superblock.ReadUsersFile(diskPath)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer users.txt: %v", err)
+	}
+	users := utils.ParseUsersFile(usersContent)
+
+	data := &LsData{Path: folderPath, Entries: []LsEntry{}}
+	for _, entry := range entries {
+		inode, err := superblock.ReadInode(diskPath, entry.Inode)
+		if err != nil {
+			return nil, fmt.Errorf("error al deserializar inodo %d: %v", entry.Inode, err)
+		}
+
+		entryType := "Archivo"
+		if inode.I_type[0] == '0' {
+			entryType = "Carpeta"
+		}
+
+		data.Entries = append(data.Entries, LsEntry{
+			Name:        entry.Name,
+			Inode:       entry.Inode,
+			Type:        entryType,
+			Permissions: utils.PermissionString(*inode),
+			Owner:       users.UserName(int(inode.I_uid)),
+			Group:       users.GroupName(int(inode.I_gid)),
+			Size:        inode.I_size,
+			Created:     formatReportDate(inode.I_ctime),
+			Modified:    formatReportDate(inode.I_mtime),
+		})
+	}
+	return data, nil
+}
+
+// ReportLs genera el listado de una carpeta de la partición: permisos, propietario,
+// grupo, tamaño, fechas, tipo y nombre de cada entrada
+func ReportLs(superblock *structures.SuperBlock, diskPath string, path string, folderPath string) error {
+	data, err := BuildLsData(superblock, diskPath, folderPath)
+	if err != nil {
+		return err
+	}
+
+	outputImage, err := writeGraph(path, lsDot(data))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Reporte LS generado:", outputImage)
+	return nil
+}
+
+// lsDot dibuja la tabla del listado
+func lsDot(data *LsData) string {
+	var dotContent strings.Builder
+	dotContent.WriteString(fmt.Sprintf(`digraph G {
+	bgcolor="#f8f9fa"
+	node [shape=plaintext fontname="Arial"]
+	tabla [label=<
+	<table border="0" cellborder="1" cellspacing="0" cellpadding="6" bgcolor="white">
+	<tr><td colspan="8" bgcolor="#2c3e50" align="center"><font color="white"><b>REPORTE LS: %s</b></font></td></tr>
+	<tr><td bgcolor="#3498db"><font color="white">Permisos</font></td><td bgcolor="#3498db"><font color="white">Propietario</font></td><td bgcolor="#3498db"><font color="white">Grupo</font></td><td bgcolor="#3498db"><font color="white">Tamaño</font></td><td bgcolor="#3498db"><font color="white">Fecha de creación</font></td><td bgcolor="#3498db"><font color="white">Fecha de modificación</font></td><td bgcolor="#3498db"><font color="white">Tipo</font></td><td bgcolor="#3498db"><font color="white">Nombre</font></td></tr>
+	`, escapeHTML(data.Path)))
+
+	for _, entry := range data.Entries {
+		dotContent.WriteString(fmt.Sprintf(`<tr><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>
+	`,
+			entry.Permissions,
+			escapeHTML(entry.Owner),
+			escapeHTML(entry.Group),
+			entry.Size,
+			entry.Created,
+			entry.Modified,
+			entry.Type,
+			escapeHTML(entry.Name)))
+	}
+
+	if len(data.Entries) == 0 {
+		dotContent.WriteString(`<tr><td colspan="8" align="center" bgcolor="#f8f9fa"><i>La carpeta está vacía</i></td></tr>`)
+	}
+
+	dotContent.WriteString("</table>>]; }")
+	return dotContent.String()
+}
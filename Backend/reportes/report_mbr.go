@@ -2,117 +2,151 @@ package reports
 
 import (
 	structures "backend/structures"
-	utils "backend/utils"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
-	"time"
 )
 
-func ReportMBR(mbr *structures.MBR, reportPath string, diskPath string) error {
-	if err := utils.CreateParentDirs(reportPath); err != nil {
-		return err
+// MBRData datos del reporte mbr: los campos del MBR y sus particiones, con la cadena de
+// EBRs de la extendida
+type MBRData struct {
+	Size         int32          `json:"size"`
+	CreationDate string         `json:"creation_date"`
+	Signature    int32          `json:"signature"`
+	Partitions   []MBRPartition `json:"partitions"`
+}
+
+type MBRPartition struct {
+	Status  string    `json:"status"`
+	Type    string    `json:"type"`
+	Fit     string    `json:"fit"`
+	Start   int32     `json:"start"`
+	Size    int32     `json:"size"`
+	Name    string    `json:"name"`
+	Logical []EBRData `json:"logical,omitempty"` // Solo en la extendida
+}
+
+type EBRData struct {
+	Fit   string `json:"fit"`
+	Start int32  `json:"start"`
+	Size  int32  `json:"size"`
+	Next  int32  `json:"next"`
+	Name  string `json:"name"`
+}
+
+// BuildMBRData lee las particiones del MBR y recorre los EBRs de la extendida
+func BuildMBRData(mbr *structures.MBR, diskPath string) (*MBRData, error) {
+	data := &MBRData{
+		Size:         mbr.Mbr_size,
+		CreationDate: formatReportDate(mbr.Mbr_creation_date),
+		Signature:    mbr.Mbr_disk_signature,
+		Partitions:   []MBRPartition{},
 	}
 
-	dotFileName, outputImage := utils.GetFileNames(reportPath)
-
-	// Colores mejorados en esquema moderno y profesional
-	dotContent := fmt.Sprintf(`digraph G {
-	bgcolor="#f8f9fa"
-	node [shape=plaintext fontname="Arial"]
-	tabla [label=<
-	<table border="0" cellborder="1" cellspacing="0" cellpadding="6" bgcolor="white">
-	<tr><td colspan="2" bgcolor="#2c3e50" align="center"><font color="white"><b>REPORTE DE MBR</b></font></td></tr>
-	<tr><td bgcolor="#ecf0f1">mbr_tamano</td><td>%d</td></tr>
-	<tr><td bgcolor="#ecf0f1">mbr_fecha_creacion</td><td>%s</td></tr>
-	<tr><td bgcolor="#ecf0f1">mbr_disk_signature</td><td>%d</td></tr>
-	`, mbr.Mbr_size, time.Unix(int64(mbr.Mbr_creation_date), 0), mbr.Mbr_disk_signature)
-
-	partIndex := 1 // contador de particiones principales
-
 	for _, part := range mbr.Mbr_partitions {
 		if part.Part_size <= 0 {
 			continue
 		}
 
-		partName := strings.TrimRight(string(part.Part_name[:]), "\x00")
-		partStatus := rune(part.Part_status[0])
-		partType := rune(part.Part_type[0])
-		partFit := rune(part.Part_fit[0])
-
-		dotContent += fmt.Sprintf(`
-		<tr><td colspan="2" bgcolor="#3498db" align="center"><font color="white"><b>Partición %d</b></font></td></tr>
-		<tr><td bgcolor="#e8f4fc">part_status</td><td>%c</td></tr>
-		<tr><td bgcolor="#e8f4fc">part_type</td><td>%c</td></tr>
-		<tr><td bgcolor="#e8f4fc">part_fit</td><td>%c</td></tr>
-		<tr><td bgcolor="#e8f4fc">part_start</td><td>%d</td></tr>
-		<tr><td bgcolor="#e8f4fc">part_size</td><td>%d</td></tr>
-		<tr><td bgcolor="#e8f4fc">part_name</td><td>%s</td></tr>
-		`, partIndex, partStatus, partType, partFit, part.Part_start, part.Part_size, partName)
-
-		partIndex++
+		partition := MBRPartition{
+			Status: string(part.Part_status[0]),
+			Type:   string(part.Part_type[0]),
+			Fit:    string(part.Part_fit[0]),
+			Start:  part.Part_start,
+			Size:   part.Part_size,
+			Name:   strings.TrimRight(string(part.Part_name[:]), "\x00"),
+		}
 
 		// Si es extendida, recorrer EBRs
-		if (partType == 'E' || partType == 'e') {
+		if strings.EqualFold(partition.Type, "E") {
+			partition.Logical = []EBRData{}
+			visited := make(map[int64]bool)
 			current := int64(part.Part_start)
-			logicalIndex := 1
-			found := false
 
-			for current != -1 {
+			for current != -1 && !visited[current] {
+				visited[current] = true
 				ebr, err := structures.ReadEBR(diskPath, current)
 				if err != nil {
 					break
 				}
 
 				if ebr.PartSize > 0 {
-					found = true
-
-					ebrName := strings.TrimRight(string(ebr.PartName[:]), "\x00")
-					ebrFit := rune(ebr.PartFit)
-
-					dotContent += fmt.Sprintf(`
-					<tr><td colspan="2" bgcolor="#9b59b6" align="center"><font color="white"><b>Partición Lógica %d</b></font></td></tr>
-					<tr><td bgcolor="#f4ecf7">part_fit</td><td>%c</td></tr>
-					<tr><td bgcolor="#f4ecf7">part_start</td><td>%d</td></tr>
-					<tr><td bgcolor="#f4ecf7">part_size</td><td>%d</td></tr>
-					<tr><td bgcolor="#f4ecf7">part_next</td><td>%d</td></tr>
-					<tr><td bgcolor="#f4ecf7">part_name</td><td>%s</td></tr>
-					`, logicalIndex, ebrFit, ebr.PartStart, ebr.PartSize, ebr.PartNext, ebrName)
-
-					logicalIndex++
-				}
-
-				if ebr.PartNext == -1 {
-					break
+					partition.Logical = append(partition.Logical, EBRData{
+						Fit:   string(ebr.PartFit),
+						Start: ebr.PartStart,
+						Size:  ebr.PartSize,
+						Next:  ebr.PartNext,
+						Name:  strings.TrimRight(string(ebr.PartName[:]), "\x00"),
+					})
 				}
 				current = int64(ebr.PartNext)
 			}
-
-			if !found {
-				dotContent += `<tr><td colspan="2" align="center" bgcolor="#f8f9fa"><i>No hay particiones lógicas</i></td></tr>`
-			}
 		}
+
+		data.Partitions = append(data.Partitions, partition)
 	}
 
-	dotContent += "</table>>]; }"
+	return data, nil
+}
 
-	file, err := os.Create(dotFileName)
+func ReportMBR(mbr *structures.MBR, reportPath string, diskPath string) error {
+	data, err := BuildMBRData(mbr, diskPath)
 	if err != nil {
-		return fmt.Errorf("error creando archivo DOT: %v", err)
-	}
-	defer file.Close()
-
-	if _, err := file.WriteString(dotContent); err != nil {
-		return fmt.Errorf("error escribiendo contenido DOT: %v", err)
+		return err
 	}
 
-	// Mejorar calidad de imagen
-	cmd := exec.Command("dot", "-Tpng", "-Gdpi=200", dotFileName, "-o", outputImage)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("error ejecutando Graphviz: %v", err)
+	// Con Graphviz se mejora la calidad de imagen
+	outputImage, err := writeGraph(reportPath, mbrDot(data), "-Gdpi=200")
+	if err != nil {
+		return err
 	}
 
 	fmt.Println("Reporte MBR generado:", outputImage)
 	return nil
-}
\ No newline at end of file
+}
+
+// mbrDot dibuja la tabla del MBR con una sección por partición
+func mbrDot(data *MBRData) string {
+	// Colores mejorados en esquema moderno y profesional
+	dotContent := fmt.Sprintf(`digraph G {
+	bgcolor="#f8f9fa"
+	node [shape=plaintext fontname="Arial"]
+	tabla [label=<
+	<table border="0" cellborder="1" cellspacing="0" cellpadding="6" bgcolor="white">
+	<tr><td colspan="2" bgcolor="#2c3e50" align="center"><font color="white"><b>REPORTE DE MBR</b></font></td></tr>
+	<tr><td bgcolor="#ecf0f1">mbr_tamano</td><td>%d</td></tr>
+	<tr><td bgcolor="#ecf0f1">mbr_fecha_creacion</td><td>%s</td></tr>
+	<tr><td bgcolor="#ecf0f1">mbr_disk_signature</td><td>%d</td></tr>
+	`, data.Size, data.CreationDate, data.Signature)
+
+	for i, part := range data.Partitions {
+		dotContent += fmt.Sprintf(`
+		<tr><td colspan="2" bgcolor="#3498db" align="center"><font color="white"><b>Partición %d</b></font></td></tr>
+		<tr><td bgcolor="#e8f4fc">part_status</td><td>%s</td></tr>
+		<tr><td bgcolor="#e8f4fc">part_type</td><td>%s</td></tr>
+		<tr><td bgcolor="#e8f4fc">part_fit</td><td>%s</td></tr>
+		<tr><td bgcolor="#e8f4fc">part_start</td><td>%d</td></tr>
+		<tr><td bgcolor="#e8f4fc">part_size</td><td>%d</td></tr>
+		<tr><td bgcolor="#e8f4fc">part_name</td><td>%s</td></tr>
+		`, i+1, escapeHTML(part.Status), escapeHTML(part.Type), escapeHTML(part.Fit), part.Start, part.Size, escapeHTML(part.Name))
+
+		if part.Logical == nil {
+			continue
+		}
+		for j, ebr := range part.Logical {
+			dotContent += fmt.Sprintf(`
+			<tr><td colspan="2" bgcolor="#9b59b6" align="center"><font color="white"><b>Partición Lógica %d</b></font></td></tr>
+			<tr><td bgcolor="#f4ecf7">part_fit</td><td>%s</td></tr>
+			<tr><td bgcolor="#f4ecf7">part_start</td><td>%d</td></tr>
+			<tr><td bgcolor="#f4ecf7">part_size</td><td>%d</td></tr>
+			<tr><td bgcolor="#f4ecf7">part_next</td><td>%d</td></tr>
+			<tr><td bgcolor="#f4ecf7">part_name</td><td>%s</td></tr>
+			`, j+1, escapeHTML(ebr.Fit), ebr.Start, ebr.Size, ebr.Next, escapeHTML(ebr.Name))
+		}
+		if len(part.Logical) == 0 {
+			dotContent += `<tr><td colspan="2" align="center" bgcolor="#f8f9fa"><i>No hay particiones lógicas</i></td></tr>`
+		}
+	}
+
+	dotContent += "</table>>]; }"
+	return dotContent
+}
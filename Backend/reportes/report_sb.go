@@ -0,0 +1,197 @@
+package reports
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/structures"
+)
+
+// SBData datos del reporte sb: los campos del superbloque, el uso de inodos y bloques y
+// la ubicación en bytes de cada área de la partición
+type SBData struct {
+	FilesystemType  int32     `json:"filesystem_type"`
+	InodesCount     int32     `json:"inodes_count"`
+	BlocksCount     int32     `json:"blocks_count"`
+	FreeInodesCount int32     `json:"free_inodes_count"`
+	FreeBlocksCount int32     `json:"free_blocks_count"`
+	Mtime           string    `json:"mtime"`
+	Umtime          string    `json:"umtime"`
+	MntCount        int32     `json:"mnt_count"`
+	Magic           string    `json:"magic"`
+	InodeSize       int32     `json:"inode_size"`
+	BlockSize       int32     `json:"block_size"`
+	FirstIno        int32     `json:"first_ino"`
+	FirstInoIndex   int32     `json:"first_ino_index"`
+	FirstBlo        int32     `json:"first_blo"`
+	FirstBloIndex   int32     `json:"first_blo_index"`
+	BmInodeStart    int32     `json:"bm_inode_start"`
+	BmBlockStart    int32     `json:"bm_block_start"`
+	InodeStart      int32     `json:"inode_start"`
+	BlockStart      int32     `json:"block_start"`
+	Usage           []SBUsage `json:"usage"`
+	Layout          []SBArea  `json:"layout"`
+}
+
+type SBUsage struct {
+	Name    string  `json:"name"`
+	Used    int32   `json:"used"`
+	Free    int32   `json:"free"`
+	Percent float64 `json:"percent"`
+}
+
+type SBArea struct {
+	Name  string `json:"name"`
+	Start int32  `json:"start"`
+	End   int32  `json:"end"`
+	Size  int32  `json:"size"`
+}
+
+// BuildSBData copia los campos del superbloque y calcula el uso y las áreas; partitionStart
+// es el byte donde inicia la partición, que es donde está el superbloque
+func BuildSBData(superblock *structures.SuperBlock, partitionStart int32) *SBData {
+	sbSize := int32(binary.Size(structures.SuperBlock{}))
+	usedInodes := superblock.S_inodes_count - superblock.S_free_inodes_count
+	usedBlocks := superblock.S_blocks_count - superblock.S_free_blocks_count
+
+	data := &SBData{
+		FilesystemType:  superblock.S_filesystem_type,
+		InodesCount:     superblock.S_inodes_count,
+		BlocksCount:     superblock.S_blocks_count,
+		FreeInodesCount: superblock.S_free_inodes_count,
+		FreeBlocksCount: superblock.S_free_blocks_count,
+		Mtime:           formatReportDate(superblock.S_mtime),
+		Umtime:          formatReportDate(superblock.S_umtime),
+		MntCount:        superblock.S_mnt_count,
+		Magic:           fmt.Sprintf("0x%X", superblock.S_magic),
+		InodeSize:       superblock.S_inode_size,
+		BlockSize:       superblock.S_block_size,
+		FirstIno:        superblock.S_first_ino,
+		FirstInoIndex:   offsetIndex(superblock.S_first_ino, superblock.S_inode_start, superblock.S_inode_size),
+		FirstBlo:        superblock.S_first_blo,
+		FirstBloIndex:   offsetIndex(superblock.S_first_blo, superblock.S_block_start, superblock.S_block_size),
+		BmInodeStart:    superblock.S_bm_inode_start,
+		BmBlockStart:    superblock.S_bm_block_start,
+		InodeStart:      superblock.S_inode_start,
+		BlockStart:      superblock.S_block_start,
+		Usage: []SBUsage{
+			{"Inodos", usedInodes, superblock.S_free_inodes_count, percent(usedInodes, superblock.S_inodes_count)},
+			{"Bloques", usedBlocks, superblock.S_free_blocks_count, percent(usedBlocks, superblock.S_blocks_count)},
+		},
+	}
+
+	// Ubicación de cada área; en EXT3 el journal va entre el superbloque y el bitmap de inodos
+	type area struct {
+		name  string
+		start int32
+		size  int32
+	}
+	areas := []area{{"Superbloque", partitionStart, sbSize}}
+	if superblock.S_filesystem_type == 3 {
+		journalStart := partitionStart + sbSize
+		areas = append(areas, area{"Journal", journalStart, superblock.S_bm_inode_start - journalStart})
+	}
+	areas = append(areas, []area{
+		{"Bitmap de inodos", superblock.S_bm_inode_start, superblock.S_inodes_count},
+		{"Bitmap de bloques", superblock.S_bm_block_start, superblock.S_blocks_count},
+		{"Tabla de inodos", superblock.S_inode_start, superblock.S_inodes_count * superblock.S_inode_size},
+		{"Bloques", superblock.S_block_start, superblock.S_blocks_count * superblock.S_block_size},
+	}...)
+	for _, area := range areas {
+		data.Layout = append(data.Layout, SBArea{Name: area.name, Start: area.start, End: area.start + area.size - 1, Size: area.size})
+	}
+	return data
+}
+
+// ReportSB genera el reporte del superbloque: todos sus campos, el uso de inodos y bloques
+// y la ubicación en bytes de cada área de la partición
+func ReportSB(superblock *structures.SuperBlock, partitionStart int32, path string) error {
+	outputImage, err := writeGraph(path, sbDot(BuildSBData(superblock, partitionStart)))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Reporte SB generado:", outputImage)
+	return nil
+}
+
+// sbDot dibuja la tabla del superbloque con sus secciones de uso y distribución
+func sbDot(data *SBData) string {
+	var dotContent strings.Builder
+	dotContent.WriteString(`digraph G {
+	bgcolor="#f8f9fa"
+	node [shape=plaintext fontname="Arial"]
+	tabla [label=<
+	<table border="0" cellborder="1" cellspacing="0" cellpadding="6" bgcolor="white">
+	<tr><td colspan="4" bgcolor="#2c3e50" align="center"><font color="white"><b>REPORTE DE SUPERBLOQUE</b></font></td></tr>
+	`)
+
+	// Campos del superbloque
+	fields := [][2]string{
+		{"s_filesystem_type", fmt.Sprintf("%d (EXT%d)", data.FilesystemType, data.FilesystemType)},
+		{"s_inodes_count", fmt.Sprint(data.InodesCount)},
+		{"s_blocks_count", fmt.Sprint(data.BlocksCount)},
+		{"s_free_inodes_count", fmt.Sprint(data.FreeInodesCount)},
+		{"s_free_blocks_count", fmt.Sprint(data.FreeBlocksCount)},
+		{"s_mtime", data.Mtime},
+		{"s_umtime", data.Umtime},
+		{"s_mnt_count", fmt.Sprint(data.MntCount)},
+		{"s_magic", data.Magic},
+		{"s_inode_size", fmt.Sprint(data.InodeSize)},
+		{"s_block_size", fmt.Sprint(data.BlockSize)},
+		{"s_first_ino", fmt.Sprintf("%d (inodo %d)", data.FirstIno, data.FirstInoIndex)},
+		{"s_first_blo", fmt.Sprintf("%d (bloque %d)", data.FirstBlo, data.FirstBloIndex)},
+		{"s_bm_inode_start", fmt.Sprint(data.BmInodeStart)},
+		{"s_bm_block_start", fmt.Sprint(data.BmBlockStart)},
+		{"s_inode_start", fmt.Sprint(data.InodeStart)},
+		{"s_block_start", fmt.Sprint(data.BlockStart)},
+	}
+	for _, field := range fields {
+		dotContent.WriteString(fmt.Sprintf(`<tr><td bgcolor="#ecf0f1">%s</td><td colspan="3">%s</td></tr>
+	`, field[0], field[1]))
+	}
+
+	// Uso de inodos y bloques
+	dotContent.WriteString(`<tr><td colspan="4" bgcolor="#3498db" align="center"><font color="white"><b>Uso</b></font></td></tr>
+	<tr><td bgcolor="#e8f4fc"></td><td bgcolor="#e8f4fc">Usados</td><td bgcolor="#e8f4fc">Libres</td><td bgcolor="#e8f4fc">% Usado</td></tr>
+	`)
+	for _, usage := range data.Usage {
+		dotContent.WriteString(fmt.Sprintf(`<tr><td bgcolor="#e8f4fc">%s</td><td>%d</td><td>%d</td><td>%.2f%%</td></tr>
+	`, usage.Name, usage.Used, usage.Free, usage.Percent))
+	}
+
+	// Ubicación de cada área
+	dotContent.WriteString(`<tr><td colspan="4" bgcolor="#9b59b6" align="center"><font color="white"><b>Distribución en bytes</b></font></td></tr>
+	<tr><td bgcolor="#f4ecf7">Área</td><td bgcolor="#f4ecf7">Inicio</td><td bgcolor="#f4ecf7">Fin</td><td bgcolor="#f4ecf7">Tamaño</td></tr>
+	`)
+	for _, area := range data.Layout {
+		dotContent.WriteString(fmt.Sprintf(`<tr><td bgcolor="#f4ecf7">%s</td><td>%d</td><td>%d</td><td>%d</td></tr>
+	`, area.Name, area.Start, area.End, area.Size))
+	}
+
+	dotContent.WriteString("</table>>]; }")
+	return dotContent.String()
+}
+
+// formatReportDate convierte una fecha guardada como segundos Unix a texto legible
+func formatReportDate(seconds float32) string {
+	return time.Unix(int64(seconds), 0).Format("2006-01-02 15:04:05")
+}
+
+// offsetIndex convierte un desplazamiento en bytes al índice del elemento dentro de su área
+func offsetIndex(offset, start, size int32) int32 {
+	if size <= 0 {
+		return -1
+	}
+	return (offset - start) / size
+}
+
+// percent devuelve el porcentaje de part sobre total
+func percent(part, total int32) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
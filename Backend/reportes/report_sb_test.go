@@ -0,0 +1,59 @@
+package reports
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"backend/structures"
+)
+
+// TestBuildSBDataLayout verifica que el superbloque se ubique al inicio de la partición
+// y que solo EXT3 tenga el área del journal entre el superbloque y el bitmap de inodos
+func TestBuildSBDataLayout(t *testing.T) {
+	const start, n = 1000, 10
+	sbSize := int32(binary.Size(structures.SuperBlock{}))
+	journalSize := int32(n * binary.Size(structures.Journal{}))
+
+	tests := []struct {
+		fs      int32
+		journal int32
+		areas   []string
+	}{
+		{2, 0, []string{"Superbloque", "Bitmap de inodos", "Bitmap de bloques", "Tabla de inodos", "Bloques"}},
+		{3, journalSize, []string{"Superbloque", "Journal", "Bitmap de inodos", "Bitmap de bloques", "Tabla de inodos", "Bloques"}},
+	}
+	for _, tt := range tests {
+		bmInodeStart := start + sbSize + tt.journal
+		superblock := &structures.SuperBlock{
+			S_filesystem_type: tt.fs,
+			S_inodes_count:    n,
+			S_blocks_count:    3 * n,
+			S_inode_size:      int32(binary.Size(structures.Inode{})),
+			S_block_size:      int32(binary.Size(structures.FolderBlock{})),
+			S_bm_inode_start:  bmInodeStart,
+			S_bm_block_start:  bmInodeStart + n,
+			S_inode_start:     bmInodeStart + 4*n,
+		}
+		superblock.S_block_start = superblock.S_inode_start + n*superblock.S_inode_size
+
+		data := BuildSBData(superblock, start)
+		if len(data.Layout) != len(tt.areas) {
+			t.Fatalf("EXT%d: %d áreas, se esperaban %d: %+v", tt.fs, len(data.Layout), len(tt.areas), data.Layout)
+		}
+		for i, area := range data.Layout {
+			if area.Name != tt.areas[i] {
+				t.Errorf("EXT%d: área %d = %s, se esperaba %s", tt.fs, i, area.Name, tt.areas[i])
+			}
+			// Las áreas van una detrás de otra, sin huecos
+			if i > 0 && area.Start != data.Layout[i-1].End+1 {
+				t.Errorf("EXT%d: %s empieza en %d, después de %d", tt.fs, area.Name, area.Start, data.Layout[i-1].End)
+			}
+		}
+		if data.Layout[0].Start != start {
+			t.Errorf("EXT%d: el superbloque empieza en %d, se esperaba %d", tt.fs, data.Layout[0].Start, start)
+		}
+		if tt.fs == 3 && data.Layout[1].Size != journalSize {
+			t.Errorf("EXT3: el journal mide %d, se esperaba %d", data.Layout[1].Size, journalSize)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	"backend/structures"
+)
+
+// TreeData datos del reporte tree: los inodos y bloques alcanzables desde la raíz y los
+// enlaces entre ellos
+type TreeData struct {
+	Inodes []InodeData `json:"inodes"`
+	Blocks []BlockData `json:"blocks"`
+	Links  []TreeLink  `json:"links"`
+}
+
+// TreeLink enlace desde el apuntador Port del nodo From (inodeN o blockN) hacia To:
+// bN es la posición en I_block, pN en un bloque de apuntadores y eN en un bloque de carpeta
+type TreeLink struct {
+	From string `json:"from"`
+	Port string `json:"port"`
+	To   string `json:"to"`
+}
+
+// BuildTreeData recorre el sistema de archivos desde el inodo raíz
+func BuildTreeData(superblock *structures.SuperBlock, diskPath string) (*TreeData, error) {
+	tree := &treeBuilder{
+		superblock:    superblock,
+		diskPath:      diskPath,
+		data:          &TreeData{Inodes: []InodeData{}, Blocks: []BlockData{}, Links: []TreeLink{}},
+		visitedInodes: make(map[int32]bool),
+		visitedBlocks: make(map[int32]bool),
+	}
+	if err := tree.inode(0); err != nil {
+		return nil, err
+	}
+	return tree.data, nil
+}
+
+// ReportTree genera el grafo completo del sistema de archivos desde el inodo raíz: cada
+// inodo con sus atributos y su arreglo I_block, cada bloque al que apunta (incluidos los
+// bloques de apuntadores) y las entradas de carpeta enlazadas a sus inodos hijos
+func ReportTree(superblock *structures.SuperBlock, diskPath string, path string) error {
+	data, err := BuildTreeData(superblock, diskPath)
+	if err != nil {
+		return err
+	}
+
+	outputImage, err := writeGraph(path, treeDot(data))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Imagen del árbol generada correctamente:", outputImage)
+	return nil
+}
+
+// treeDot dibuja los inodos, los bloques y sus enlaces
+func treeDot(data *TreeData) string {
+	var dotContent strings.Builder
+	dotContent.WriteString(`digraph G {
+		rankdir=LR;
+		node [shape=plaintext]
+	`)
+
+	for _, inode := range data.Inodes {
+		dotContent.WriteString(fmt.Sprintf("inode%d [label=<%s>];\n", inode.Index, treeInodeLabel(inode)))
+	}
+	for _, block := range data.Blocks {
+		dotContent.WriteString(fmt.Sprintf("block%d [label=<%s>];\n", block.Index, blockLabel(block)))
+	}
+	for _, link := range data.Links {
+		dotContent.WriteString(fmt.Sprintf("%s:%s -> %s;\n", link.From, link.Port, link.To))
+	}
+
+	dotContent.WriteString("}")
+	return dotContent.String()
+}
+
+// treeBuilder recorre el sistema de archivos anotando los inodos, bloques y enlaces;
+// cada inodo y bloque se anota una sola vez
+type treeBuilder struct {
+	superblock    *structures.SuperBlock
+	diskPath      string
+	data          *TreeData
+	visitedInodes map[int32]bool
+	visitedBlocks map[int32]bool
+}
+
+// link anota un enlace del árbol
+func (t *treeBuilder) link(from string, port string, to string) {
+	t.data.Links = append(t.data.Links, TreeLink{From: from, Port: port, To: to})
+}
+
+// validInode indica si el índice corresponde a un inodo de la partición
+func (t *treeBuilder) validInode(index int32) bool {
+	return index >= 0 && index < t.superblock.S_inodes_count
+}
+
+// validBlock indica si el índice corresponde a un bloque de la partición
+func (t *treeBuilder) validBlock(index int32) bool {
+	return index >= 0 && index < t.superblock.S_blocks_count
+}
+
+// inode anota el inodo y todo lo que cuelga de él
+func (t *treeBuilder) inode(index int32) error {
+	if t.visitedInodes[index] || !t.validInode(index) {
+		return nil
+	}
+	t.visitedInodes[index] = true
+
+	inode, err := t.superblock.ReadInode(t.diskPath, index)
+	if err != nil {
+		return fmt.Errorf("error al deserializar inodo %d: %v", index, err)
+	}
+	t.data.Inodes = append(t.data.Inodes, newInodeData(index, inode))
+
+	dataKind := structures.BlockFile
+	if inode.I_type[0] == '0' {
+		dataKind = structures.BlockFolder
+	}
+
+	for i, pointer := range inode.I_block {
+		// Un apuntador fuera de rango no tiene bloque que dibujar, así que tampoco se enlaza
+		if pointer == -1 || !t.validBlock(pointer) {
+			continue
+		}
+		// Los apuntadores 12, 13 y 14 son indirectos de nivel 1, 2 y 3
+		level := 0
+		if i >= structures.DirectBlocks {
+			level = i - structures.DirectBlocks + 1
+		}
+		t.link(fmt.Sprintf("inode%d", index), fmt.Sprintf("b%d", i), fmt.Sprintf("block%d", pointer))
+		if err := t.block(pointer, level, dataKind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// block anota un bloque según su tipo; los de apuntadores bajan un nivel por cada
+// apuntador y los de carpeta enlazan cada entrada con su inodo
+func (t *treeBuilder) block(index int32, level int, dataKind structures.BlockKind) error {
+	if t.visitedBlocks[index] || !t.validBlock(index) {
+		return nil
+	}
+	t.visitedBlocks[index] = true
+
+	kind := dataKind
+	if level > 0 {
+		kind = structures.BlockPointer
+	}
+	block, err := readBlockData(t.superblock, t.diskPath, index, kind)
+	if err != nil {
+		return err
+	}
+	t.data.Blocks = append(t.data.Blocks, block)
+
+	for i, pointer := range block.Pointers {
+		if pointer == -1 || !t.validBlock(pointer) {
+			continue
+		}
+		t.link(fmt.Sprintf("block%d", index), fmt.Sprintf("p%d", i), fmt.Sprintf("block%d", pointer))
+		if err := t.block(pointer, level-1, dataKind); err != nil {
+			return err
+		}
+	}
+
+	for i, entry := range block.Entries {
+		// "." y ".." apuntan hacia arriba; se omiten para no volver a dibujar el árbol
+		if entry.Inode == -1 || entry.Name == "" || entry.Name == "." || entry.Name == ".." || !t.validInode(entry.Inode) {
+			continue
+		}
+		t.link(fmt.Sprintf("block%d", index), fmt.Sprintf("e%d", i), fmt.Sprintf("inode%d", entry.Inode))
+		if err := t.inode(entry.Inode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// treeInodeLabel tabla con los atributos del inodo y su arreglo I_block; cada apuntador
+// tiene el puerto bN para enlazarlo con su bloque
+func treeInodeLabel(inode InodeData) string {
+	var label strings.Builder
+	label.WriteString(`<table border="0" cellborder="1" cellspacing="0">`)
+	label.WriteString(fmt.Sprintf(`<tr><td colspan="2" bgcolor="#a9dfbf"><b>INODO %d</b></td></tr>`, inode.Index))
+	label.WriteString(fmt.Sprintf(`<tr><td>UID</td><td>%d</td></tr>`, inode.UID))
+	label.WriteString(fmt.Sprintf(`<tr><td>GID</td><td>%d</td></tr>`, inode.GID))
+	label.WriteString(fmt.Sprintf(`<tr><td>Size</td><td>%d</td></tr>`, inode.Size))
+	label.WriteString(fmt.Sprintf(`<tr><td>Atime</td><td>%s</td></tr>`, inode.Atime))
+	label.WriteString(fmt.Sprintf(`<tr><td>Ctime</td><td>%s</td></tr>`, inode.Ctime))
+	label.WriteString(fmt.Sprintf(`<tr><td>Mtime</td><td>%s</td></tr>`, inode.Mtime))
+	label.WriteString(fmt.Sprintf(`<tr><td>Tipo</td><td>%s</td></tr>`, escapeHTML(inode.Type)))
+	label.WriteString(fmt.Sprintf(`<tr><td>Perm</td><td>%s</td></tr>`, escapeHTML(inode.Perm)))
+	for i, pointer := range inode.Blocks {
+		name := fmt.Sprintf("AD%d", i)
+		if i >= structures.DirectBlocks {
+			name = fmt.Sprintf("AI%d", i-structures.DirectBlocks+1)
+		}
+		label.WriteString(fmt.Sprintf(`<tr><td>%s</td><td port="b%d">%d</td></tr>`, name, i, pointer))
+	}
+	label.WriteString("</table>")
+	return label.String()
+}
@@ -0,0 +1,62 @@
+package reports
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"backend/structures"
+)
+
+// TestBuildTreeDataSkipsOutOfRange arma un sistema de archivos mínimo cuya raíz apunta a
+// un bloque fuera de rango y cuya carpeta tiene una entrada con un inodo fuera de rango:
+// ninguno de los dos debe producir un enlace hacia un nodo que no se dibuja
+func TestBuildTreeDataSkipsOutOfRange(t *testing.T) {
+	diskPath := filepath.Join(t.TempDir(), "tree.mia")
+	inodeSize := int32(binary.Size(structures.Inode{}))
+	superblock := &structures.SuperBlock{
+		S_inodes_count: 2,
+		S_blocks_count: 2,
+		S_inode_size:   inodeSize,
+		S_block_size:   int32(binary.Size(structures.FolderBlock{})),
+		S_inode_start:  0,
+		S_block_start:  2 * inodeSize,
+	}
+
+	root := &structures.Inode{I_type: [1]byte{'0'}, I_perm: [3]byte{'7', '7', '7'}}
+	for i := range root.I_block {
+		root.I_block[i] = -1
+	}
+	root.I_block[0] = 0
+	root.I_block[1] = 99
+	if err := root.Serialize(diskPath, int64(superblock.S_inode_start)); err != nil {
+		t.Fatal(err)
+	}
+
+	folder := &structures.FolderBlock{}
+	for i := range folder.B_content {
+		folder.B_content[i].B_inodo = -1
+	}
+	copy(folder.B_content[0].B_name[:], ".")
+	folder.B_content[0].B_inodo = 0
+	copy(folder.B_content[1].B_name[:], "..")
+	folder.B_content[1].B_inodo = 0
+	copy(folder.B_content[2].B_name[:], "roto")
+	folder.B_content[2].B_inodo = 500
+	if err := folder.Serialize(diskPath, int64(superblock.S_block_start)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := BuildTreeData(superblock, diskPath)
+	if err != nil {
+		t.Fatalf("BuildTreeData: %v", err)
+	}
+
+	if len(data.Inodes) != 1 || len(data.Blocks) != 1 {
+		t.Fatalf("se esperaba 1 inodo y 1 bloque, hay %d y %d", len(data.Inodes), len(data.Blocks))
+	}
+	want := TreeLink{From: "inode0", Port: "b0", To: "block0"}
+	if len(data.Links) != 1 || data.Links[0] != want {
+		t.Errorf("enlaces = %+v, se esperaba solo %+v", data.Links, want)
+	}
+}
@@ -0,0 +1,321 @@
+package reports
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Separación entre rangos y entre nodos del mismo rango, en pixeles
+const (
+	svgRankSep = 60.0
+	svgNodeSep = 24.0
+	svgMargin  = 12.0
+)
+
+// svgNode nodo del grafo con su forma ya medida y su posición final
+type svgNode struct {
+	dot    *dotNode
+	table  *htmlTable
+	record *recordField
+	text   string
+	shape  string
+	fill   string
+	ports  map[string]rect
+	w, h   float64
+	x, y   float64
+	rank   int
+	order  float64
+}
+
+// renderSVG dibuja el grafo: cada nodo se mide según su forma (tabla HTML, registro o
+// texto), se asigna a un rango según las aristas y los rangos se colocan uno tras otro
+// en la dirección de rankdir
+func renderSVG(g *dotGraph) (string, error) {
+	horizontal := strings.EqualFold(g.attrs["rankdir"], "LR") || strings.EqualFold(g.attrs["rankdir"], "RL")
+
+	nodes := make([]*svgNode, 0, len(g.nodes))
+	index := make(map[string]*svgNode)
+	for _, dn := range g.nodes {
+		node, err := newSVGNode(g, dn, horizontal)
+		if err != nil {
+			return "", fmt.Errorf("nodo %s: %v", dn.id, err)
+		}
+		nodes = append(nodes, node)
+		index[dn.id] = node
+	}
+
+	ranks := assignRanks(g, nodes, index)
+	placeRanks(ranks, horizontal)
+
+	// Tamaño del lienzo
+	width, height := 0.0, 0.0
+	for _, node := range nodes {
+		width = math.Max(width, node.x+node.w)
+		height = math.Max(height, node.y+node.h)
+	}
+	width += 2 * svgMargin
+	height += 2 * svgMargin
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Arial, Helvetica, sans-serif" font-size="%.0f">`+"\n",
+		width, height, width, height, svgFontSize))
+	sb.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="black"/></marker></defs>` + "\n")
+	sb.WriteString(fmt.Sprintf(`<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgFill(defaultString(g.attrs["bgcolor"], "white"))))
+	sb.WriteString(fmt.Sprintf(`<g transform="translate(%.0f,%.0f)">`+"\n", svgMargin, svgMargin))
+
+	for _, edge := range g.edges {
+		from, to := index[edge.from], index[edge.to]
+		if from == nil || to == nil {
+			continue
+		}
+		drawEdge(&sb, from, edge.fromPort, to, edge.toPort, horizontal)
+	}
+	for _, node := range nodes {
+		node.draw(&sb)
+	}
+
+	sb.WriteString("</g>\n</svg>\n")
+	return sb.String(), nil
+}
+
+// newSVGNode interpreta la etiqueta del nodo según su forma y calcula su tamaño
+func newSVGNode(g *dotGraph, dn *dotNode, horizontal bool) (*svgNode, error) {
+	node := &svgNode{dot: dn, shape: strings.ToLower(defaultString(g.attr(dn, "shape"), "ellipse"))}
+	label, hasLabel := dn.attrs["label"]
+	if !hasLabel {
+		label = dn.id
+	}
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(label), "<") && strings.Contains(strings.ToLower(label), "<table"):
+		table, err := parseHTMLLabel(label)
+		if err != nil {
+			return nil, err
+		}
+		table.layout()
+		node.table = table
+		node.ports = table.ports()
+		node.w, node.h = table.w, table.h
+	case node.shape == "record" || node.shape == "mrecord":
+		node.record = parseRecordLabel(label)
+		// En rankdir=LR Graphviz gira los registros: el primer nivel va en vertical
+		w, h := node.record.measure(!horizontal)
+		node.record.place(0, 0, w, h, !horizontal)
+		node.ports = node.record.ports(make(map[string]rect))
+		node.w, node.h = w, h
+		if strings.Contains(g.attr(dn, "style"), "filled") {
+			node.fill = defaultString(g.attr(dn, "fillcolor"), "lightgrey")
+		}
+	default:
+		table, err := parseHTMLLabel(strings.ReplaceAll(escapeHTML(label), `\n`, "<br/>"))
+		if err != nil {
+			return nil, err
+		}
+		table.cellpadding = 6
+		table.layout()
+		node.table = table
+		node.text = label
+		node.w, node.h = table.w, table.h
+		if node.shape != "plaintext" && node.shape != "plain" && node.shape != "none" {
+			node.w += 24
+			node.h += 12
+		}
+	}
+	return node, nil
+}
+
+// draw escribe el nodo en su posición
+func (n *svgNode) draw(sb *strings.Builder) {
+	switch {
+	case n.record != nil:
+		n.record.draw(sb, n.x, n.y, func(port string) string {
+			if attrs, ok := n.dot.portAttrs[port]; ok && attrs["fillcolor"] != "" {
+				return attrs["fillcolor"]
+			}
+			return n.fill
+		})
+	case n.text != "":
+		switch n.shape {
+		case "plaintext", "plain", "none":
+		case "box", "rect", "rectangle", "square":
+			sb.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="white" stroke="black"/>`+"\n", n.x, n.y, n.w, n.h))
+		default:
+			sb.WriteString(fmt.Sprintf(`<ellipse cx="%.1f" cy="%.1f" rx="%.1f" ry="%.1f" fill="white" stroke="black"/>`+"\n", n.x+n.w/2, n.y+n.h/2, n.w/2, n.h/2))
+		}
+		n.table.draw(sb, n.x+(n.w-n.table.w)/2, n.y+(n.h-n.table.h)/2)
+	default:
+		n.table.draw(sb, n.x, n.y)
+	}
+}
+
+// assignRanks asigna a cada nodo el rango del camino más largo desde un nodo sin
+// aristas de entrada, ignorando las aristas que cierran ciclos, y ordena cada rango
+// por el promedio de la posición de sus predecesores
+func assignRanks(g *dotGraph, nodes []*svgNode, index map[string]*svgNode) [][]*svgNode {
+	successors := make(map[*svgNode][]*svgNode)
+	for _, edge := range g.edges {
+		from, to := index[edge.from], index[edge.to]
+		if from != nil && to != nil && from != to {
+			successors[from] = append(successors[from], to)
+		}
+	}
+
+	// Búsqueda en profundidad para descartar las aristas de regreso
+	const (
+		unvisited = iota
+		inStack
+		done
+	)
+	state := make(map[*svgNode]int)
+	predecessors := make(map[*svgNode][]*svgNode)
+	var order []*svgNode
+	var visit func(node *svgNode)
+	visit = func(node *svgNode) {
+		state[node] = inStack
+		for _, next := range successors[node] {
+			switch state[next] {
+			case unvisited:
+				predecessors[next] = append(predecessors[next], node)
+				visit(next)
+			case done:
+				predecessors[next] = append(predecessors[next], node)
+			}
+		}
+		state[node] = done
+		order = append(order, node)
+	}
+	for _, node := range nodes {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+
+	// El recorrido en postorden invertido es un orden topológico
+	maxRank := 0
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		for _, pred := range predecessors[node] {
+			if pred.rank+1 > node.rank {
+				node.rank = pred.rank + 1
+			}
+		}
+		if node.rank > maxRank {
+			maxRank = node.rank
+		}
+	}
+
+	ranks := make([][]*svgNode, maxRank+1)
+	for i, node := range nodes {
+		node.order = float64(i)
+		ranks[node.rank] = append(ranks[node.rank], node)
+	}
+	for r := 1; r < len(ranks); r++ {
+		for i, node := range ranks[r-1] {
+			node.order = float64(i)
+		}
+		for i, node := range ranks[r] {
+			node.order = float64(i)
+			sum, count := 0.0, 0
+			for _, pred := range predecessors[node] {
+				if pred.rank == r-1 {
+					sum += pred.order
+					count++
+				}
+			}
+			if count > 0 {
+				node.order = sum / float64(count)
+			}
+		}
+		sort.SliceStable(ranks[r], func(i, j int) bool { return ranks[r][i].order < ranks[r][j].order })
+	}
+	return ranks
+}
+
+// placeRanks coloca los rangos uno tras otro y centra los nodos de cada rango
+func placeRanks(ranks [][]*svgNode, horizontal bool) {
+	// Extensión de cada rango a lo largo del eje de los rangos y a lo ancho
+	depths := make([]float64, len(ranks))
+	breadths := make([]float64, len(ranks))
+	maxBreadth := 0.0
+	for r, rank := range ranks {
+		for i, node := range rank {
+			depth, breadth := node.h, node.w
+			if horizontal {
+				depth, breadth = node.w, node.h
+			}
+			depths[r] = math.Max(depths[r], depth)
+			breadths[r] += breadth
+			if i > 0 {
+				breadths[r] += svgNodeSep
+			}
+		}
+		maxBreadth = math.Max(maxBreadth, breadths[r])
+	}
+
+	position := 0.0
+	for r, rank := range ranks {
+		offset := (maxBreadth - breadths[r]) / 2
+		for _, node := range rank {
+			if horizontal {
+				node.x = position + (depths[r]-node.w)/2
+				node.y = offset
+				offset += node.h + svgNodeSep
+			} else {
+				node.x = offset
+				node.y = position + (depths[r]-node.h)/2
+				offset += node.w + svgNodeSep
+			}
+		}
+		position += depths[r] + svgRankSep
+	}
+}
+
+// drawEdge dibuja la arista como una curva desde el lado del puerto de origen que mira
+// hacia el destino hasta el lado opuesto del destino
+func drawEdge(sb *strings.Builder, from *svgNode, fromPort string, to *svgNode, toPort string, horizontal bool) {
+	a := from.portBox(fromPort)
+	b := to.portBox(toPort)
+
+	var x1, y1, x2, y2, cx1, cy1, cx2, cy2 float64
+	if horizontal {
+		y1, y2 = a.y+a.h/2, b.y+b.h/2
+		if b.x+b.w/2 >= a.x+a.w/2 {
+			// El puerto de origen sale por el borde derecho del nodo para no cruzar la tabla
+			x1, x2 = from.x+from.w, b.x
+		} else {
+			x1, x2 = from.x, b.x+b.w
+		}
+		bend := (x2 - x1) / 2
+		cx1, cy1, cx2, cy2 = x1+bend, y1, x2-bend, y2
+	} else {
+		x1, x2 = a.x+a.w/2, b.x+b.w/2
+		if b.y+b.h/2 >= a.y+a.h/2 {
+			y1, y2 = from.y+from.h, b.y
+		} else {
+			y1, y2 = from.y, b.y+b.h
+		}
+		bend := (y2 - y1) / 2
+		cx1, cy1, cx2, cy2 = x1, y1+bend, x2, y2-bend
+	}
+
+	sb.WriteString(fmt.Sprintf(`<path d="M%.1f,%.1f C%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="none" stroke="black" marker-end="url(#arrow)"/>`+"\n",
+		x1, y1, cx1, cy1, cx2, cy2, x2, y2))
+}
+
+// portBox devuelve la posición absoluta del puerto, o la del nodo si no lo tiene
+func (n *svgNode) portBox(port string) rect {
+	if box, ok := n.ports[port]; ok && port != "" {
+		return rect{x: n.x + box.x, y: n.y + box.y, w: box.w, h: box.h}
+	}
+	return rect{x: n.x, y: n.y, w: n.w, h: n.h}
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,140 @@
+package reports
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"backend/structures"
+)
+
+// sampleReportDots DOT de cada reporte gráfico, generado con datos de ejemplo que
+// incluyen los caracteres que hay que escapar
+func sampleReportDots() map[string]string {
+	content := "hola <mundo> & \"amigos\"\n"
+	blocks := []int32{0, 1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 2, -1, -1}
+	inodes := []InodeData{
+		{Index: 0, UID: 1, GID: 1, Size: 0, Atime: "2026-01-01 00:00:00", Ctime: "2026-01-01 00:00:00", Mtime: "2026-01-01 00:00:00", Type: "0", Perm: "777", Blocks: blocks},
+		{Index: 1, UID: 1, GID: 1, Size: int32(len(content)), Atime: "2026-01-01 00:00:00", Ctime: "2026-01-01 00:00:00", Mtime: "2026-01-01 00:00:00", Type: "1", Perm: "664", Blocks: []int32{3, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}},
+	}
+	folder := BlockData{Index: 0, Kind: structures.BlockFolder, Entries: []FolderEntryData{{".", 0}, {"..", 0}, {"a<b>.txt", 1}, {"", -1}}}
+	file := BlockData{Index: 3, Kind: structures.BlockFile, Content: &content}
+	pointers := BlockData{Index: 2, Kind: structures.BlockPointer, Pointers: []int32{4, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}}
+
+	return map[string]string{
+		"mbr": mbrDot(&MBRData{Size: 1024, CreationDate: "2026-01-01 00:00:00", Signature: 7, Partitions: []MBRPartition{
+			{Status: "1", Type: "P", Fit: "F", Start: 153, Size: 300, Name: "P<1>"},
+			{Status: "0", Type: "E", Fit: "W", Start: 453, Size: 400, Name: "Ext", Logical: []EBRData{{Fit: "B", Start: 453, Size: 100, Next: -1, Name: "L&1"}}},
+		}}),
+		"disk": diskDot(&DiskData{Size: 1024, Table: "MBR", Segments: []DiskSegment{
+			{Kind: "mbr", Name: "MBR", Start: 0, Size: 153},
+			{Kind: "primary", Name: "P1", Start: 153, Size: 300, Percent: 29.3},
+			{Kind: "extended", Name: "Ext", Start: 453, Size: 400, Percent: 39.1, Segments: []DiskSegment{
+				{Kind: "ebr", Name: "EBR", Start: 453, Size: 30},
+				{Kind: "logical", Name: "L1", Start: 483, Size: 70, Percent: 6.8},
+				{Kind: "free", Start: 553, Size: 300, Percent: 29.3},
+			}},
+			{Kind: "free", Start: 853, Size: 171, Percent: 16.7},
+		}}),
+		"gpt_disk": diskDot(&DiskData{Size: 4096, Table: "GPT", Segments: []DiskSegment{
+			{Kind: "mbr", Name: "MBR", Start: 0, Size: 153},
+			{Kind: "gpt", Name: "Encabezado y tabla GPT", Start: 153, Size: 900, Percent: 22},
+			{Kind: "primary", Name: "datos {x|y}", Start: 1053, Size: 3043, Percent: 74.3},
+		}}),
+		"inode": inodeDot(&InodeTableData{InodesCount: 2, Inodes: inodes}),
+		"block": blockDot(&BlockTableData{BlocksCount: 4, Blocks: []BlockData{folder, pointers, file}}),
+		"sb": sbDot(&SBData{FilesystemType: 3, InodesCount: 10, BlocksCount: 30, FreeInodesCount: 8, FreeBlocksCount: 27,
+			Mtime: "2026-01-01 00:00:00", Umtime: "2026-01-01 00:00:00", MntCount: 1, Magic: "0xEF53",
+			Usage:  []SBUsage{{Name: "Inodos", Used: 2, Free: 8, Percent: 20}},
+			Layout: []SBArea{{Name: "Superbloque", Start: 0, End: 68, Size: 68}}}),
+		"ls": lsDot(&LsData{Path: "/home", Entries: []LsEntry{
+			{Name: "a<b>.txt", Inode: 1, Type: "Archivo", Permissions: "-rw-rw-r--", Owner: "root", Group: "root", Size: 12, Created: "2026-01-01 00:00:00", Modified: "2026-01-01 00:00:00"},
+		}}),
+		"tree": treeDot(&TreeData{
+			Inodes: inodes,
+			Blocks: []BlockData{folder, file},
+			Links:  []TreeLink{{From: "inode0", Port: "b0", To: "block0"}, {From: "block0", Port: "e2", To: "inode1"}, {From: "inode1", Port: "b0", To: "block3"}},
+		}),
+		"journaling": journalingDot(&JournalingData{Entries: []JournalEntry{
+			{Count: 1, Operation: "mkfile", Path: "/a&b.txt", Content: content, Date: "2026-01-01 00:00:00"},
+		}}),
+		"journaling_empty": journalingDot(&JournalingData{}),
+	}
+}
+
+// TestRenderSVGWellFormed dibuja cada reporte con el renderizador propio y verifica que
+// el resultado sea XML bien formado con un elemento svg de tamaño positivo
+func TestRenderSVGWellFormed(t *testing.T) {
+	for name, dot := range sampleReportDots() {
+		graph, err := parseDot(dot)
+		if err != nil {
+			t.Errorf("%s: parseDot: %v", name, err)
+			continue
+		}
+		svg, err := renderSVG(graph)
+		if err != nil {
+			t.Errorf("%s: renderSVG: %v", name, err)
+			continue
+		}
+		if err := checkSVG(svg, len(graph.nodes)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// TestRenderSVGEscapesText verifica que el texto de las etiquetas se escape en el SVG
+func TestRenderSVGEscapesText(t *testing.T) {
+	graph, err := parseDot(sampleReportDots()["ls"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg, err := renderSVG(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(svg, "a&lt;b&gt;.txt") {
+		t.Errorf("el nombre con < y > no aparece escapado en el SVG")
+	}
+}
+
+// checkSVG recorre el documento con el decodificador XML: falla si no está bien formado,
+// si la raíz no es svg o si dibuja menos rectángulos que nodos tiene el grafo
+func checkSVG(svg string, nodes int) error {
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	root := ""
+	rects := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root == "" {
+			root = start.Name.Local
+			for _, attr := range start.Attr {
+				if (attr.Name.Local == "width" || attr.Name.Local == "height") && (attr.Value == "" || attr.Value == "0") {
+					return errors.New("el svg no tiene tamaño")
+				}
+			}
+		}
+		if start.Name.Local == "rect" {
+			rects++
+		}
+	}
+	if root != "svg" {
+		return errors.New("la raíz del documento no es svg: " + root)
+	}
+	// El primer rectángulo es el fondo
+	if rects-1 < nodes {
+		return errors.New("hay menos rectángulos que nodos en el SVG")
+	}
+	return nil
+}
@@ -0,0 +1,562 @@
+package reports
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Medidas del texto del renderizador SVG, en pixeles
+const (
+	svgFontSize   = 14.0
+	svgLineHeight = 18.0
+)
+
+// rect rectángulo relativo a la esquina del nodo; se usa para ubicar los puertos
+type rect struct {
+	x, y, w, h float64
+}
+
+// textWidth estima el ancho del texto en Arial a partir del ancho típico de cada letra
+func textWidth(text string, bold bool) float64 {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("ijlI.,:;'|!", r):
+			width += 0.28
+		case strings.ContainsRune("ft()[]{}r-/ ", r):
+			width += 0.36
+		case strings.ContainsRune("mwMW%@", r):
+			width += 0.86
+		case r >= 'A' && r <= 'Z':
+			width += 0.68
+		case r >= '0' && r <= '9':
+			width += 0.56
+		default:
+			width += 0.55
+		}
+	}
+	if bold {
+		width *= 1.08
+	}
+	return width * svgFontSize
+}
+
+// escapeXML escapa un texto para el contenido o los atributos del SVG
+func escapeXML(text string) string {
+	return html.EscapeString(text)
+}
+
+// ---------- Etiquetas HTML (shape=plaintext) ----------
+
+// htmlSpan fragmento de texto con el mismo estilo
+type htmlSpan struct {
+	text   string
+	bold   bool
+	italic bool
+	color  string
+}
+
+type htmlLine []htmlSpan
+
+func (l htmlLine) width() float64 {
+	width := 0.0
+	for _, span := range l {
+		width += textWidth(span.text, span.bold)
+	}
+	return width
+}
+
+type htmlCell struct {
+	lines   []htmlLine
+	colspan int
+	bgcolor string
+	align   string
+	port    string
+	col     int
+	box     rect
+}
+
+// htmlTable tabla de una etiqueta HTML con las medidas calculadas por layout
+type htmlTable struct {
+	border      float64
+	cellborder  float64
+	cellspacing float64
+	cellpadding float64
+	bgcolor     string
+	rows        [][]*htmlCell
+	w, h        float64
+}
+
+var htmlAttrRegex = regexp.MustCompile(`([A-Za-z_]+)\s*=\s*"([^"]*)"`)
+
+// parseHTMLLabel interpreta las etiquetas table, tr, td, font, b, i y br de una
+// etiqueta HTML; un texto sin tabla se trata como una tabla de una celda sin bordes
+func parseHTMLLabel(label string) (*htmlTable, error) {
+	var table *htmlTable
+	var row []*htmlCell
+	var cell *htmlCell
+	styles := []htmlSpan{{}}
+
+	addText := func(text string) {
+		text = strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+		if text == "" {
+			return
+		}
+		if cell == nil {
+			if table != nil {
+				return
+			}
+			// Texto suelto fuera de una tabla
+			table = newHTMLTable(map[string]string{"border": "0", "cellborder": "0"})
+			cell = &htmlCell{colspan: 1}
+		}
+		if len(cell.lines) == 0 {
+			cell.lines = append(cell.lines, htmlLine{})
+		}
+		last := &cell.lines[len(cell.lines)-1]
+		span := styles[len(styles)-1]
+		// Los espacios entre etiquetas separan palabras de estilos distintos
+		if len(*last) > 0 {
+			span.text = " " + text
+		} else {
+			span.text = text
+		}
+		*last = append(*last, span)
+	}
+
+	for i := 0; i < len(label); {
+		if label[i] != '<' {
+			end := strings.IndexByte(label[i:], '<')
+			if end < 0 {
+				end = len(label) - i
+			}
+			addText(label[i : i+end])
+			i += end
+			continue
+		}
+
+		end := strings.IndexByte(label[i:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("etiqueta HTML sin cerrar")
+		}
+		tag := strings.TrimSpace(label[i+1 : i+end])
+		i += end + 1
+
+		closing := strings.HasPrefix(tag, "/")
+		tag = strings.TrimSuffix(strings.TrimPrefix(tag, "/"), "/")
+		name := strings.ToLower(strings.Fields(tag + " ")[0])
+		attrs := make(map[string]string)
+		for _, match := range htmlAttrRegex.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(match[1])] = match[2]
+		}
+
+		switch name {
+		case "table":
+			if !closing && table == nil {
+				table = newHTMLTable(attrs)
+			}
+		case "tr":
+			if closing {
+				if table != nil && len(row) > 0 {
+					table.rows = append(table.rows, row)
+				}
+				row = nil
+			}
+		case "td":
+			if !closing {
+				colspan, err := strconv.Atoi(attrs["colspan"])
+				if err != nil || colspan < 1 {
+					colspan = 1
+				}
+				cell = &htmlCell{colspan: colspan, bgcolor: attrs["bgcolor"], align: attrs["align"], port: attrs["port"]}
+			} else if cell != nil {
+				row = append(row, cell)
+				cell = nil
+			}
+		case "br":
+			if cell != nil {
+				cell.lines = append(cell.lines, htmlLine{})
+			}
+		case "b", "i", "font":
+			if closing {
+				if len(styles) > 1 {
+					styles = styles[:len(styles)-1]
+				}
+				continue
+			}
+			style := styles[len(styles)-1]
+			switch name {
+			case "b":
+				style.bold = true
+			case "i":
+				style.italic = true
+			case "font":
+				if color, ok := attrs["color"]; ok {
+					style.color = color
+				}
+			}
+			styles = append(styles, style)
+		}
+	}
+
+	if table == nil {
+		table = newHTMLTable(map[string]string{"border": "0", "cellborder": "0"})
+	}
+	// Texto suelto sin tabla
+	if cell != nil && len(table.rows) == 0 {
+		table.rows = append(table.rows, []*htmlCell{cell})
+	}
+	return table, nil
+}
+
+// newHTMLTable crea la tabla con los valores por defecto de Graphviz
+func newHTMLTable(attrs map[string]string) *htmlTable {
+	number := func(name string, fallback float64) float64 {
+		value, err := strconv.ParseFloat(attrs[name], 64)
+		if err != nil {
+			return fallback
+		}
+		return value
+	}
+	table := &htmlTable{
+		border:      number("border", 1),
+		cellspacing: number("cellspacing", 2),
+		cellpadding: number("cellpadding", 2),
+		bgcolor:     attrs["bgcolor"],
+	}
+	table.cellborder = number("cellborder", table.border)
+	return table
+}
+
+// layout calcula el ancho de cada columna, el alto de cada fila y la posición de las celdas
+func (t *htmlTable) layout() {
+	columns := 0
+	for _, row := range t.rows {
+		count := 0
+		for _, cell := range row {
+			cell.col = count
+			count += cell.colspan
+		}
+		if count > columns {
+			columns = count
+		}
+	}
+	if columns == 0 {
+		t.w, t.h = 2*t.border+8, 2*t.border+8
+		return
+	}
+
+	inner := 2 * (t.cellpadding + t.cellborder)
+	natural := func(cell *htmlCell) (float64, float64) {
+		width := 0.0
+		for _, line := range cell.lines {
+			if w := line.width(); w > width {
+				width = w
+			}
+		}
+		lines := len(cell.lines)
+		if lines == 0 {
+			lines = 1
+		}
+		return width + inner + 4, float64(lines)*svgLineHeight + inner
+	}
+
+	// Primero las celdas simples y luego las que abarcan varias columnas
+	widths := make([]float64, columns)
+	for _, row := range t.rows {
+		for _, cell := range row {
+			if w, _ := natural(cell); cell.colspan == 1 && w > widths[cell.col] {
+				widths[cell.col] = w
+			}
+		}
+	}
+	for _, row := range t.rows {
+		for _, cell := range row {
+			if cell.colspan == 1 {
+				continue
+			}
+			end := cell.col + cell.colspan
+			if end > columns {
+				end = columns
+			}
+			span := float64(end-cell.col-1) * t.cellspacing
+			for _, w := range widths[cell.col:end] {
+				span += w
+			}
+			if w, _ := natural(cell); w > span {
+				extra := (w - span) / float64(end-cell.col)
+				for c := cell.col; c < end; c++ {
+					widths[c] += extra
+				}
+			}
+		}
+	}
+
+	offsets := make([]float64, columns+1)
+	offsets[0] = t.border + t.cellspacing
+	for c, w := range widths {
+		offsets[c+1] = offsets[c] + w + t.cellspacing
+	}
+
+	y := t.border + t.cellspacing
+	for _, row := range t.rows {
+		height := 0.0
+		for _, cell := range row {
+			if _, h := natural(cell); h > height {
+				height = h
+			}
+		}
+		for _, cell := range row {
+			end := cell.col + cell.colspan
+			if end > columns {
+				end = columns
+			}
+			cell.box = rect{x: offsets[cell.col], y: y, w: offsets[end] - offsets[cell.col] - t.cellspacing, h: height}
+		}
+		y += height + t.cellspacing
+	}
+
+	t.w = offsets[columns] + t.border
+	t.h = y + t.border
+}
+
+// ports devuelve la posición de las celdas que tienen puerto
+func (t *htmlTable) ports() map[string]rect {
+	ports := make(map[string]rect)
+	for _, row := range t.rows {
+		for _, cell := range row {
+			if cell.port != "" {
+				ports[cell.port] = cell.box
+			}
+		}
+	}
+	return ports
+}
+
+// draw escribe la tabla en el SVG con la esquina superior izquierda en (x, y)
+func (t *htmlTable) draw(sb *strings.Builder, x, y float64) {
+	if t.bgcolor != "" || t.border > 0 {
+		sb.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" %s/>`+"\n",
+			x, y, t.w, t.h, svgFill(t.bgcolor), svgStroke(t.border)))
+	}
+	for _, row := range t.rows {
+		for _, cell := range row {
+			box := cell.box
+			if cell.bgcolor != "" || t.cellborder > 0 {
+				sb.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" %s/>`+"\n",
+					x+box.x, y+box.y, box.w, box.h, svgFill(cell.bgcolor), svgStroke(t.cellborder)))
+			}
+
+			inset := t.cellpadding + t.cellborder
+			top := y + box.y + (box.h-float64(len(cell.lines))*svgLineHeight)/2
+			for i, line := range cell.lines {
+				textX, anchor := x+box.x+box.w/2, "middle"
+				switch strings.ToLower(cell.align) {
+				case "left":
+					textX, anchor = x+box.x+inset, "start"
+				case "right":
+					textX, anchor = x+box.x+box.w-inset, "end"
+				}
+				writeSVGText(sb, textX, top+(float64(i)+0.5)*svgLineHeight, anchor, line)
+			}
+		}
+	}
+}
+
+// writeSVGText escribe una línea de texto centrada verticalmente en y
+func writeSVGText(sb *strings.Builder, x, y float64, anchor string, line htmlLine) {
+	sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" text-anchor="%s" dominant-baseline="central">`, x, y, anchor))
+	for _, span := range line {
+		attrs := []string{`xml:space="preserve"`}
+		if span.bold {
+			attrs = append(attrs, `font-weight="bold"`)
+		}
+		if span.italic {
+			attrs = append(attrs, `font-style="italic"`)
+		}
+		if span.color != "" {
+			attrs = append(attrs, fmt.Sprintf(`fill="%s"`, escapeXML(span.color)))
+		}
+		sb.WriteString(fmt.Sprintf(`<tspan %s>%s</tspan>`, strings.Join(attrs, " "), escapeXML(span.text)))
+	}
+	sb.WriteString("</text>\n")
+}
+
+func svgFill(color string) string {
+	if color == "" {
+		return "none"
+	}
+	return escapeXML(color)
+}
+
+func svgStroke(width float64) string {
+	if width <= 0 {
+		return `stroke="none"`
+	}
+	return fmt.Sprintf(`stroke="black" stroke-width="%.1f"`, width)
+}
+
+// ---------- Registros (shape=record) ----------
+
+// recordField campo de un registro; los grupos {a|b} tienen campos hijos y se dibujan
+// en la orientación contraria a la de su padre
+type recordField struct {
+	port   string
+	text   string
+	fields []*recordField
+	group  bool
+	box    rect
+}
+
+// parseRecordLabel interpreta una etiqueta de registro: campos separados por |, grupos
+// entre llaves y puertos <nombre> al inicio de cada campo
+func parseRecordLabel(label string) *recordField {
+	runes := []rune(label)
+	pos := 0
+	return parseRecordGroup(runes, &pos)
+}
+
+func parseRecordGroup(runes []rune, pos *int) *recordField {
+	group := &recordField{group: true}
+	var text strings.Builder
+	var child *recordField
+
+	flush := func() {
+		if child == nil {
+			child = parseRecordText(text.String())
+		}
+		group.fields = append(group.fields, child)
+		text.Reset()
+		child = nil
+	}
+
+	for *pos < len(runes) {
+		r := runes[*pos]
+		*pos++
+		switch r {
+		case '\\':
+			if *pos < len(runes) {
+				next := runes[*pos]
+				*pos++
+				if next == 'n' || next == 'l' || next == 'r' {
+					text.WriteRune('\n')
+				} else {
+					text.WriteRune(next)
+				}
+			}
+		case '{':
+			child = parseRecordGroup(runes, pos)
+		case '}':
+			flush()
+			return group
+		case '|':
+			flush()
+		default:
+			if child == nil {
+				text.WriteRune(r)
+			}
+		}
+	}
+	flush()
+	return group
+}
+
+// parseRecordText separa el puerto <nombre> del texto de un campo
+func parseRecordText(text string) *recordField {
+	field := &recordField{}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "<") {
+		if end := strings.IndexByte(text, '>'); end > 0 {
+			field.port = strings.TrimSpace(text[1:end])
+			text = strings.TrimSpace(text[end+1:])
+		}
+	}
+	field.text = text
+	return field
+}
+
+// measure calcula el tamaño natural del campo
+func (f *recordField) measure(horizontal bool) (float64, float64) {
+	if !f.group {
+		lines := strings.Split(f.text, "\n")
+		width := 0.0
+		for _, line := range lines {
+			if w := textWidth(line, false); w > width {
+				width = w
+			}
+		}
+		f.box.w = width + 16
+		f.box.h = float64(len(lines))*svgLineHeight + 18
+		return f.box.w, f.box.h
+	}
+
+	f.box.w, f.box.h = 0, 0
+	for _, child := range f.fields {
+		w, h := child.measure(!horizontal)
+		if horizontal {
+			f.box.w += w
+			if h > f.box.h {
+				f.box.h = h
+			}
+		} else {
+			f.box.h += h
+			if w > f.box.w {
+				f.box.w = w
+			}
+		}
+	}
+	return f.box.w, f.box.h
+}
+
+// place ubica el campo y reparte el espacio sobrante entre sus hijos
+func (f *recordField) place(x, y, w, h float64, horizontal bool) {
+	extraW, extraH := w-f.box.w, h-f.box.h
+	f.box = rect{x: x, y: y, w: w, h: h}
+	if !f.group || len(f.fields) == 0 {
+		return
+	}
+	extraW /= float64(len(f.fields))
+	extraH /= float64(len(f.fields))
+	for _, child := range f.fields {
+		if horizontal {
+			cw := child.box.w + extraW
+			child.place(x, y, cw, h, !horizontal)
+			x += cw
+		} else {
+			ch := child.box.h + extraH
+			child.place(x, y, w, ch, !horizontal)
+			y += ch
+		}
+	}
+}
+
+// ports devuelve la posición de los campos que tienen puerto
+func (f *recordField) ports(ports map[string]rect) map[string]rect {
+	if f.port != "" {
+		ports[f.port] = f.box
+	}
+	for _, child := range f.fields {
+		child.ports(ports)
+	}
+	return ports
+}
+
+// draw escribe los campos hoja del registro; fill devuelve el color de cada puerto
+func (f *recordField) draw(sb *strings.Builder, x, y float64, fill func(port string) string) {
+	if f.group {
+		for _, child := range f.fields {
+			child.draw(sb, x, y, fill)
+		}
+		return
+	}
+	sb.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="black"/>`+"\n",
+		x+f.box.x, y+f.box.y, f.box.w, f.box.h, svgFill(fill(f.port))))
+	lines := strings.Split(f.text, "\n")
+	top := y + f.box.y + (f.box.h-float64(len(lines))*svgLineHeight)/2
+	for i, line := range lines {
+		writeSVGText(sb, x+f.box.x+f.box.w/2, top+(float64(i)+0.5)*svgLineHeight, "middle", htmlLine{{text: line}})
+	}
+}
@@ -1,48 +1,112 @@
 package stores
 
-type AuthStore struct {
+import (
+	"sync"
+	"time"
+)
+
+// SessionInfo copia de los datos de una sesión en un momento dado; se puede leer sin
+// candados aunque la sesión cambie después
+type SessionInfo struct {
 	IsLoggedIn  bool
 	Username    string
-	Password    string
 	PartitionID string
 	UserID      int
 	GroupID     int
 }
-var Auth = &AuthStore{
-	IsLoggedIn:  false,
-	Username:    "",
-	Password:    "",
-	PartitionID: "",
-	UserID:      -1,
-	GroupID:     -1,
+
+// AuthStore es una sesión: el usuario que inició sesión y la partición en la que lo hizo.
+// Las sesiones HTTP se guardan en el almacén de sesiones con un token; la terminal usa Auth.
+// Varias peticiones pueden usar la misma sesión a la vez (o cerrarla con unmount), así que
+// sus datos solo se leen y modifican con mu tomado.
+type AuthStore struct {
+	mu        sync.RWMutex
+	info      SessionInfo
+	token     string    // Vacío para la sesión por defecto
+	expiresAt time.Time // Solo aplica a sesiones con token
+}
+
+// Auth es la sesión por defecto, la que usan los comandos ejecutados desde la terminal
+var Auth = NewSession()
+
+// NewSession crea una sesión vacía, sin usuario
+func NewSession() *AuthStore {
+	return &AuthStore{info: loggedOut()}
+}
+
+func loggedOut() SessionInfo {
+	return SessionInfo{IsLoggedIn: false, Username: "", PartitionID: "", UserID: -1, GroupID: -1}
 }
 
-func (a *AuthStore) Login(username, password, partitionID string, uid, gid int) {
-	a.IsLoggedIn = true
-	a.Username = username
-	a.Password = password
-	a.PartitionID = partitionID
-	a.UserID = uid
-	a.GroupID = gid
+func (a *AuthStore) Login(username, partitionID string, uid, gid int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.info = SessionInfo{IsLoggedIn: true, Username: username, PartitionID: partitionID, UserID: uid, GroupID: gid}
 }
 
 func (a *AuthStore) Logout() {
-	a.IsLoggedIn = false
-	a.Username = ""
-	a.Password = ""
-	a.PartitionID = ""
-	a.UserID = -1
-	a.GroupID = -1
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.info = loggedOut()
+}
+
+// LogoutIfPartition cierra la sesión solo si está abierta en partitionID
+func (a *AuthStore) LogoutIfPartition(partitionID string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.info.IsLoggedIn || a.info.PartitionID != partitionID {
+		return false
+	}
+	a.info = loggedOut()
+	return true
+}
+
+// Info devuelve una copia de los datos de la sesión; quien necesite varios campos debe
+// leerlos de la misma copia para que sean coherentes entre sí
+func (a *AuthStore) Info() SessionInfo {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.info
 }
 
 func (a *AuthStore) IsAuthenticated() bool {
-	return a.IsLoggedIn
+	return a.Info().IsLoggedIn
 }
 
-func (a *AuthStore) GetCurrentUser() (string, string, string) {
-	return a.Username, a.Password, a.PartitionID
+func (a *AuthStore) GetCurrentUser() (string, string) {
+	info := a.Info()
+	return info.Username, info.PartitionID
 }
 
 func (a *AuthStore) GetPartitionID() string {
-	return a.PartitionID
-}
\ No newline at end of file
+	return a.Info().PartitionID
+}
+
+// Token devuelve el token de la sesión, vacío si no está en el almacén
+func (a *AuthStore) Token() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.token
+}
+
+// ExpiresAt devuelve cuándo vence la sesión si no tiene actividad
+func (a *AuthStore) ExpiresAt() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.expiresAt
+}
+
+// renew asigna el token de la sesión y extiende su vencimiento
+func (a *AuthStore) renew(token string, ttl time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = token
+	a.expiresAt = time.Now().Add(ttl)
+}
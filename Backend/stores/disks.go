@@ -0,0 +1,89 @@
+package stores
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Información de un disco creado con mkdisk
+type DiskInfo struct {
+	Path string `json:"path"` // Ruta del archivo del disco
+	Name string `json:"name"` // Nombre del archivo (ej: Disco1.mia)
+}
+
+// Catálogo de discos creados, indexado por path; se accede a él con stateMu tomado
+var disks map[string]DiskInfo = make(map[string]DiskInfo)
+
+// RegisterDisk agrega un disco al catálogo
+func RegisterDisk(path string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	disks[path] = DiskInfo{Path: path, Name: filepath.Base(path)}
+}
+
+// UnregisterDisk quita un disco del catálogo
+func UnregisterDisk(path string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	delete(disks, path)
+}
+
+// GetDiskByName busca un disco del catálogo por su nombre de archivo (con o sin extensión)
+func GetDiskByName(name string) (DiskInfo, bool) {
+	for _, disk := range ListDisks() {
+		baseName := strings.TrimSuffix(disk.Name, filepath.Ext(disk.Name))
+		if strings.EqualFold(disk.Name, name) || strings.EqualFold(baseName, name) {
+			return disk, true
+		}
+	}
+	return DiskInfo{}, false
+}
+
+// ListDisks devuelve los discos del catálogo ordenados por nombre
+func ListDisks() []DiskInfo {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	list := make([]DiskInfo, 0, len(disks))
+	for _, disk := range disks {
+		list = append(list, disk)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Path < list[j].Path
+	})
+	return list
+}
+
+// GetMountedIDsByDisk devuelve los IDs montados de un disco, ordenados
+func GetMountedIDsByDisk(path string) []string {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	ids := []string{}
+	for id, info := range mountedPartitions {
+		if info.Path == path {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetMountedIDByName devuelve el ID con el que está montada una partición, o "" si no lo está
+func GetMountedIDByName(path string, name string) string {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	for id, info := range mountedPartitions {
+		if info.Path == path && strings.EqualFold(info.Name, name) {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package stores
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Variables de entorno de la política de bloqueo de login
+const (
+	LoginMaxAttemptsEnv = "MIA_LOGIN_MAX_ATTEMPTS" // Intentos fallidos antes de bloquear
+	LoginLockoutEnv     = "MIA_LOGIN_LOCKOUT"      // Duración del bloqueo (formato de time.ParseDuration)
+)
+
+const (
+	defaultLoginMaxAttempts = 3
+	defaultLoginLockout     = 5 * time.Minute
+)
+
+// loginAttempts son los intentos fallidos de un usuario de una partición
+type loginAttempts struct {
+	failures    int
+	lockedUntil time.Time
+}
+
+// Intentos fallidos por partición (disco y nombre) y usuario
+var (
+	attempts   = make(map[string]*loginAttempts)
+	attemptsMu sync.Mutex
+)
+
+// LoginMaxAttempts devuelve cuántos intentos fallidos se permiten antes de bloquear la cuenta
+func LoginMaxAttempts() int {
+	if value := os.Getenv(LoginMaxAttemptsEnv); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultLoginMaxAttempts
+}
+
+// LoginLockout devuelve cuánto dura el bloqueo de una cuenta
+func LoginLockout() time.Duration {
+	if value := os.Getenv(LoginLockoutEnv); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultLoginLockout
+}
+
+// LoginLockedFor devuelve el tiempo que le queda de bloqueo al usuario en la partición, o 0
+func LoginLockedFor(partition MountInfo, username string) time.Duration {
+	attemptsMu.Lock()
+	defer attemptsMu.Unlock()
+
+	entry, ok := attempts[attemptsKey(partition, username)]
+	if !ok {
+		return 0
+	}
+	if remaining := time.Until(entry.lockedUntil); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// RegisterLoginFailure suma un intento fallido. Devuelve cuánto dura el bloqueo si con este
+// intento se alcanzó el límite (0 si no) y cuántos intentos quedan.
+func RegisterLoginFailure(partition MountInfo, username string) (time.Duration, int) {
+	attemptsMu.Lock()
+	defer attemptsMu.Unlock()
+
+	key := attemptsKey(partition, username)
+	entry, ok := attempts[key]
+	if !ok {
+		entry = &loginAttempts{}
+		attempts[key] = entry
+	}
+
+	// Un bloqueo vencido empieza una cuenta nueva
+	if !entry.lockedUntil.IsZero() && time.Now().After(entry.lockedUntil) {
+		*entry = loginAttempts{}
+	}
+
+	entry.failures++
+	maxAttempts := LoginMaxAttempts()
+	if entry.failures >= maxAttempts {
+		lockout := LoginLockout()
+		entry.lockedUntil = time.Now().Add(lockout)
+		entry.failures = 0
+		return lockout, 0
+	}
+	return 0, maxAttempts - entry.failures
+}
+
+// ResetLoginFailures olvida los intentos fallidos después de un login correcto
+func ResetLoginFailures(partition MountInfo, username string) {
+	attemptsMu.Lock()
+	defer attemptsMu.Unlock()
+
+	delete(attempts, attemptsKey(partition, username))
+}
+
+// La clave usa el disco y el nombre de la partición y no su ID de montaje, porque los IDs
+// se reutilizan después de unmount y un bloqueo no debe pasar a otra partición ni
+// borrarse al volver a montar. Los nombres de usuario no distinguen mayúsculas en login,
+// así que la clave tampoco.
+func attemptsKey(partition MountInfo, username string) string {
+	return partition.Path + "|" + strings.ToLower(partition.Name) + "/" + strings.ToLower(username)
+}
@@ -0,0 +1,69 @@
+package stores
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginLockout(t *testing.T) {
+	t.Setenv(LoginMaxAttemptsEnv, "3")
+	t.Setenv(LoginLockoutEnv, "200ms")
+
+	partition := MountInfo{Path: "/tmp/lockout/D1.mia", Name: "Part1", Letter: "A", Correlative: 1}
+	defer ResetLoginFailures(partition, "root")
+
+	for remaining := 2; remaining >= 1; remaining-- {
+		lockout, left := RegisterLoginFailure(partition, "root")
+		if lockout != 0 || left != remaining {
+			t.Fatalf("RegisterLoginFailure = %v, %d; se esperaba 0, %d", lockout, left, remaining)
+		}
+		if LoginLockedFor(partition, "root") != 0 {
+			t.Fatal("la cuenta se bloqueó antes de llegar al límite")
+		}
+	}
+	if lockout, left := RegisterLoginFailure(partition, "ROOT"); lockout != 200*time.Millisecond || left != 0 {
+		t.Fatalf("el tercer intento devolvió %v, %d; se esperaba el bloqueo", lockout, left)
+	}
+
+	tests := []struct {
+		desc      string
+		partition MountInfo
+		username  string
+		locked    bool
+	}{
+		{"mismo usuario", partition, "root", true},
+		{"otras mayúsculas", partition, "Root", true},
+		// Otro ID de montaje para la misma partición (después de unmount y mount)
+		{"montada con otro ID", MountInfo{Path: partition.Path, Name: "PART1", Letter: "B", Correlative: 3}, "root", true},
+		{"otra partición del disco", MountInfo{Path: partition.Path, Name: "Part2", Letter: "A", Correlative: 2}, "root", false},
+		{"mismo nombre en otro disco", MountInfo{Path: "/tmp/lockout/D2.mia", Name: "Part1", Letter: "A", Correlative: 1}, "root", false},
+		{"otro usuario", partition, "user1", false},
+	}
+	for _, tt := range tests {
+		if locked := LoginLockedFor(tt.partition, tt.username) > 0; locked != tt.locked {
+			t.Errorf("%s: bloqueado = %v, se esperaba %v", tt.desc, locked, tt.locked)
+		}
+	}
+
+	// Al vencer el bloqueo se empieza una cuenta nueva
+	time.Sleep(300 * time.Millisecond)
+	if LoginLockedFor(partition, "root") != 0 {
+		t.Fatal("el bloqueo no venció")
+	}
+	if lockout, left := RegisterLoginFailure(partition, "root"); lockout != 0 || left != 2 {
+		t.Errorf("después del bloqueo RegisterLoginFailure = %v, %d; se esperaba 0, 2", lockout, left)
+	}
+}
+
+func TestResetLoginFailures(t *testing.T) {
+	t.Setenv(LoginMaxAttemptsEnv, "2")
+
+	partition := MountInfo{Path: "/tmp/lockout/D1.mia", Name: "Part1"}
+	RegisterLoginFailure(partition, "user1")
+	ResetLoginFailures(partition, "user1")
+
+	if lockout, left := RegisterLoginFailure(partition, "user1"); lockout != 0 || left != 1 {
+		t.Errorf("después de un login correcto RegisterLoginFailure = %v, %d; se esperaba 0, 1", lockout, left)
+	}
+	ResetLoginFailures(partition, "user1")
+}
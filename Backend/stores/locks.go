@@ -0,0 +1,72 @@
+package stores
+
+import (
+	"fmt"
+	"sync"
+)
+
+// stateMu protege la tabla de montaje y el catálogo de discos
+var stateMu sync.RWMutex
+
+// Un candado de lectura/escritura por archivo de disco, para que los comandos
+// sobre discos distintos puedan ejecutarse en paralelo. Las rutas de disco se limpian al
+// leer los parámetros de mkdisk, rmdisk, fdisk y mount, así que aquí se usan tal cual.
+var (
+	diskLocks   = make(map[string]*sync.RWMutex)
+	diskLocksMu sync.Mutex
+)
+
+func diskLock(path string) *sync.RWMutex {
+	diskLocksMu.Lock()
+	defer diskLocksMu.Unlock()
+
+	lock, ok := diskLocks[path]
+	if !ok {
+		lock = &sync.RWMutex{}
+		diskLocks[path] = lock
+	}
+	return lock
+}
+
+// LockDisk toma el candado de escritura del disco y devuelve la función que lo libera.
+// Lo usan los comandos que modifican la imagen del disco.
+func LockDisk(path string) func() {
+	lock := diskLock(path)
+	lock.Lock()
+	return lock.Unlock
+}
+
+// RLockDisk toma el candado de lectura del disco y devuelve la función que lo libera.
+// Lo usan los comandos que solo leen la imagen del disco.
+func RLockDisk(path string) func() {
+	lock := diskLock(path)
+	lock.RLock()
+	return lock.RUnlock
+}
+
+// LockPartition toma el candado de escritura del disco de una partición montada
+func LockPartition(id string) (func(), error) {
+	return lockPartition(id, LockDisk)
+}
+
+// RLockPartition toma el candado de lectura del disco de una partición montada
+func RLockPartition(id string) (func(), error) {
+	return lockPartition(id, RLockDisk)
+}
+
+// lockPartition toma el candado del disco de la partición con lock. Mientras se esperaba
+// el candado la partición pudo desmontarse y su ID quedar en otro disco, así que después
+// de tomarlo se verifica que la entrada de la tabla de montaje siga siendo la misma.
+func lockPartition(id string, lock func(string) func()) (func(), error) {
+	info, ok := GetMountInfo(id)
+	if !ok {
+		return nil, fmt.Errorf("la partición %s no está montada", id)
+	}
+
+	unlock := lock(info.Path)
+	if current, ok := GetMountInfo(id); !ok || current != info {
+		unlock()
+		return nil, fmt.Errorf("la partición %s no está montada", id)
+	}
+	return unlock, nil
+}
@@ -12,20 +12,57 @@ const Carnet string = "78"
 
 // Información completa de una partición montada
 type MountInfo struct {
-	Path        string // Ruta del disco
-	Name        string // Nombre de la partición
-	Letter      string // Letra asignada al disco (A, B, C...)
-	Correlative int    // Número de partición montada (1, 2, 3...)
+	Path        string `json:"path"`        // Ruta del disco
+	Name        string `json:"name"`        // Nombre de la partición
+	Letter      string `json:"letter"`      // Letra asignada al disco (A, B, C...)
+	Correlative int    `json:"correlative"` // Número de partición montada (1, 2, 3...)
 }
 
-// Declaración de variables globales
+// Declaración de variables globales; se accede a ellas con stateMu tomado
 var (
-	MountedPartitions map[string]MountInfo = make(map[string]MountInfo)
+	mountedPartitions map[string]MountInfo = make(map[string]MountInfo)
 )
 
-// GetMountedPartition obtiene la partición montada con el id especificado
-func GetMountedPartition(id string) (*structures.Partition, string, error) {
-	info, ok := MountedPartitions[id]
+// GetMountInfo devuelve la entrada de la tabla de montaje con el id especificado
+func GetMountInfo(id string) (MountInfo, bool) {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	info, ok := mountedPartitions[id]
+	return info, ok
+}
+
+// AddMountedPartition registra una partición en la tabla de montaje
+func AddMountedPartition(id string, info MountInfo) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	mountedPartitions[id] = info
+}
+
+// RemoveMountedPartition quita una partición de la tabla de montaje
+func RemoveMountedPartition(id string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	delete(mountedPartitions, id)
+}
+
+// ListMountedPartitions devuelve una copia de la tabla de montaje
+func ListMountedPartitions() map[string]MountInfo {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	list := make(map[string]MountInfo, len(mountedPartitions))
+	for id, info := range mountedPartitions {
+		list[id] = info
+	}
+	return list
+}
+
+// GetMountedPartition obtiene el descriptor de la partición montada (primaria o lógica) con el id especificado
+func GetMountedPartition(id string) (*structures.MountedPartition, string, error) {
+	info, ok := GetMountInfo(id)
 	if !ok {
 		return nil, "", errors.New("la partición no está montada")
 	}
@@ -37,8 +74,8 @@ func GetMountedPartition(id string) (*structures.Partition, string, error) {
 		return nil, "", err
 	}
 
-	partition, _ := mbr.GetPartitionByName(info.Name)
-	if partition == nil {
+	partition, err := mbr.FindMountablePartition(info.Name, path)
+	if err != nil {
 		return nil, "", errors.New("partición no encontrada")
 	}
 
@@ -48,7 +85,7 @@ func GetMountedPartition(id string) (*structures.Partition, string, error) {
 
 // GetMountedPartitionRep obtiene el MBR y SuperBlock de la partición montada
 func GetMountedPartitionRep(id string) (*structures.MBR, *structures.SuperBlock, string, error) {
-	info, exists := MountedPartitions[id]
+	info, exists := GetMountInfo(id)
 	if !exists {
 		return nil, nil, "", errors.New("la partición no está montada")
 	}
@@ -61,25 +98,14 @@ func GetMountedPartitionRep(id string) (*structures.MBR, *structures.SuperBlock,
 		return nil, nil, "", err
 	}
 
-	// Buscar partición primaria o extendida
-	partition, _ := mbr.GetPartitionByName(info.Name)
-	if partition != nil {
-		var sb structures.SuperBlock
-		err := sb.Deserialize(path, int64(partition.Part_start))
-		if err != nil {
-			return nil, nil, "", err
-		}
-		return &mbr, &sb, path, nil
-	}
-
-	// Si no es primaria, buscar como partición lógica
-	ebr, err := mbr.GetLogicalPartitionByName(info.Name, path)
+	// Buscar partición primaria o lógica
+	partition, err := mbr.FindMountablePartition(info.Name, path)
 	if err != nil {
 		return nil, nil, "", errors.New("partición no encontrada")
 	}
 
 	var sb structures.SuperBlock
-	err = sb.Deserialize(path, int64(ebr.PartStart))
+	err = sb.Deserialize(path, int64(partition.Start))
 	if err != nil {
 		return nil, nil, "", err
 	}
@@ -89,56 +115,34 @@ func GetMountedPartitionRep(id string) (*structures.MBR, *structures.SuperBlock,
 
 
 
-// GetMountedPartitionSuperblock obtiene el SuperBlock y partición montada con el id
-func GetMountedPartitionSuperblock(id string) (*structures.SuperBlock, *structures.Partition, string, error) {
-	info, ok := MountedPartitions[id]
-	if !ok {
-		return nil, nil, "", errors.New("la partición no está montada")
-	}
-	path := info.Path
-
-	var mbr structures.MBR
-	err := mbr.Deserialize(path)
+// GetMountedPartitionSuperblock obtiene el SuperBlock y el descriptor de la partición montada con el id
+func GetMountedPartitionSuperblock(id string) (*structures.SuperBlock, *structures.MountedPartition, string, error) {
+	partition, path, err := GetMountedPartition(id)
 	if err != nil {
 		return nil, nil, "", err
 	}
 
-	// Buscar partición primaria
-	partition, _ := mbr.GetPartitionByName(info.Name)
-	if partition != nil {
-		var sb structures.SuperBlock
-		err := sb.Deserialize(path, int64(partition.Part_start))
-		if err != nil {
-			return nil, nil, "", err
-		}
-		return &sb, partition, path, nil
-	}
-
-	// Si no está como primaria, buscar lógica
-	ebr, err := mbr.GetLogicalPartitionByName(info.Name, path)
-	if err != nil {
-		return nil, nil, "", errors.New("partición no encontrada")
-	}
-
 	var sb structures.SuperBlock
-	err = sb.Deserialize(path, int64(ebr.PartStart))
+	err = sb.Deserialize(path, int64(partition.Start))
 	if err != nil {
 		return nil, nil, "", err
 	}
 
-	// Retornamos nil como partición porque es lógica, o puedes ajustar la firma si necesitas manejar EBRs aparte
-	return &sb, nil, path, nil
+	return &sb, partition, path, nil
 }
 
 
 // ShowMountedPartitions imprime los IDs de todas las particiones montadas
 func ShowMountedPartitions() string {
-	if len(MountedPartitions) == 0 {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	if len(mountedPartitions) == 0 {
 		return "No hay particiones montadas."
 	}
 
 	grouped := make(map[string][]string)
-	for id := range MountedPartitions {
+	for id := range mountedPartitions {
 		prefix := id[:len(id)-1]
 		grouped[prefix] = append(grouped[prefix], id)
 	}
@@ -0,0 +1,97 @@
+package stores
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"sort"
+	"time"
+)
+
+// Variable de entorno para cambiar la carpeta de los reportes generados por la API HTTP
+const ReportsDirEnv = "MIA_REPORTS_DIR"
+
+// Carpeta por defecto de los reportes de la API HTTP (relativa al directorio del servidor)
+const defaultReportsDir = "reports"
+
+// ReportsDir devuelve la carpeta donde se guardan los reportes pedidos por la API HTTP
+func ReportsDir() string {
+	if dir := os.Getenv(ReportsDirEnv); dir != "" {
+		return dir
+	}
+	return defaultReportsDir
+}
+
+// Información de un reporte generado con rep
+type ReportInfo struct {
+	ID          string    `json:"id"`           // Identificador con el que se descarga
+	PartitionID string    `json:"partition_id"` // ID de la partición montada
+	Name        string    `json:"name"`         // Tipo de reporte (mbr, disk, tree...)
+	Path        string    `json:"path"`         // Ruta del archivo generado
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Registro de reportes generados, indexado por ID; se accede a él con stateMu tomado
+var reports map[string]ReportInfo = make(map[string]ReportInfo)
+
+// RegisterReport anota un reporte generado y le asigna un ID nuevo. Un reporte anterior
+// con la misma ruta se reemplaza, porque su archivo ya fue sobrescrito.
+func RegisterReport(partitionID string, name string, path string) (ReportInfo, error) {
+	buffer := make([]byte, 8)
+	if _, err := rand.Read(buffer); err != nil {
+		return ReportInfo{}, err
+	}
+	report := ReportInfo{
+		ID:          hex.EncodeToString(buffer),
+		PartitionID: partitionID,
+		Name:        name,
+		Path:        path,
+		CreatedAt:   time.Now(),
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	for id, previous := range reports {
+		if previous.Path == path {
+			delete(reports, id)
+		}
+	}
+	reports[report.ID] = report
+	return report, nil
+}
+
+// RestoreReport vuelve a agregar un reporte leído del archivo de estado
+func RestoreReport(report ReportInfo) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	reports[report.ID] = report
+}
+
+// GetReport busca un reporte por su ID
+func GetReport(id string) (ReportInfo, bool) {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	report, ok := reports[id]
+	return report, ok
+}
+
+// ListReports devuelve los reportes generados del más antiguo al más reciente
+func ListReports() []ReportInfo {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	list := make([]ReportInfo, 0, len(reports))
+	for _, report := range reports {
+		list = append(list, report)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
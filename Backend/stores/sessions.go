@@ -0,0 +1,106 @@
+package stores
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Variable de entorno para cambiar la duración de las sesiones HTTP (formato de time.ParseDuration)
+const SessionTTLEnv = "MIA_SESSION_TTL"
+
+// Duración por defecto de una sesión sin actividad
+const defaultSessionTTL = 30 * time.Minute
+
+var (
+	ErrSessionNotFound = errors.New("la sesión no existe")
+	ErrSessionExpired  = errors.New("la sesión expiró")
+)
+
+// Sesiones HTTP activas: token -> sesión
+var (
+	sessions   = make(map[string]*AuthStore)
+	sessionsMu sync.Mutex
+)
+
+// SessionTTL devuelve cuánto dura una sesión sin actividad
+func SessionTTL() time.Duration {
+	if value := os.Getenv(SessionTTLEnv); value != "" {
+		if ttl, err := time.ParseDuration(value); err == nil && ttl > 0 {
+			return ttl
+		}
+	}
+	return defaultSessionTTL
+}
+
+// RegisterSession guarda la sesión en el almacén y le asigna un token nuevo
+func RegisterSession(session *AuthStore) (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buffer)
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	purgeExpiredSessions()
+	session.renew(token, SessionTTL())
+	sessions[token] = session
+	return token, nil
+}
+
+// GetSession busca la sesión del token y extiende su vencimiento
+func GetSession(token string) (*AuthStore, error) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	session, ok := sessions[token]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if time.Now().After(session.ExpiresAt()) {
+		delete(sessions, token)
+		return nil, ErrSessionExpired
+	}
+	session.renew(token, SessionTTL())
+	return session, nil
+}
+
+// EndSession cierra la sesión del token y la quita del almacén
+func EndSession(token string) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	if session, ok := sessions[token]; ok {
+		session.Logout()
+		delete(sessions, token)
+	}
+}
+
+// LogoutPartition cierra todas las sesiones abiertas en la partición, incluida la de la terminal
+func LogoutPartition(partitionID string) {
+	Auth.LogoutIfPartition(partitionID)
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	for token, session := range sessions {
+		if session.LogoutIfPartition(partitionID) {
+			delete(sessions, token)
+		}
+	}
+}
+
+// purgeExpiredSessions elimina las sesiones vencidas; se llama con sessionsMu tomado
+func purgeExpiredSessions() {
+	now := time.Now()
+	for token, session := range sessions {
+		if now.After(session.ExpiresAt()) {
+			delete(sessions, token)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package stores
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionExpiry(t *testing.T) {
+	t.Setenv(SessionTTLEnv, "200ms")
+
+	session := NewSession()
+	session.Login("root", "781A", 1, 1)
+	token, err := RegisterSession(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cada uso extiende el vencimiento
+	for i := 0; i < 3; i++ {
+		time.Sleep(100 * time.Millisecond)
+		if _, err := GetSession(token); err != nil {
+			t.Fatalf("uso %d: %v", i, err)
+		}
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if _, err := GetSession(token); err != ErrSessionExpired {
+		t.Fatalf("GetSession devolvió %v, se esperaba %v", err, ErrSessionExpired)
+	}
+	// La sesión vencida ya no está en el almacén
+	if _, err := GetSession(token); err != ErrSessionNotFound {
+		t.Errorf("GetSession devolvió %v, se esperaba %v", err, ErrSessionNotFound)
+	}
+}
+
+func TestSessionTTLFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", defaultSessionTTL},
+		{"10m", 10 * time.Minute},
+		{"abc", defaultSessionTTL},
+		{"-1m", defaultSessionTTL},
+	}
+	for _, tt := range tests {
+		t.Setenv(SessionTTLEnv, tt.value)
+		if got := SessionTTL(); got != tt.want {
+			t.Errorf("SessionTTL con %q = %v, se esperaba %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLogoutPartitionEndsItsSessions(t *testing.T) {
+	first, second := NewSession(), NewSession()
+	first.Login("root", "781A", 1, 1)
+	second.Login("root", "782A", 1, 1)
+	firstToken, err := RegisterSession(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondToken, err := RegisterSession(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer EndSession(secondToken)
+
+	LogoutPartition("781A")
+
+	if _, err := GetSession(firstToken); err != ErrSessionNotFound {
+		t.Errorf("la sesión de 781A sigue activa: %v", err)
+	}
+	if first.IsAuthenticated() {
+		t.Error("la sesión de 781A sigue autenticada")
+	}
+	if session, err := GetSession(secondToken); err != nil || !session.IsAuthenticated() {
+		t.Errorf("la sesión de 782A se cerró: %v", err)
+	}
+}
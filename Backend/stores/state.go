@@ -0,0 +1,97 @@
+package stores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Variable de entorno para cambiar la ubicación del archivo de estado
+const StateFileEnv = "MIA_STATE_FILE"
+
+// Ubicación por defecto del archivo de estado (relativa al directorio del servidor)
+const defaultStateFile = "mia_state.json"
+
+// State es el contenido que se guarda en el archivo de estado
+type State struct {
+	MountedPartitions map[string]MountInfo  `json:"mounted_partitions"` // ID -> partición montada
+	Disks             map[string]DiskInfo   `json:"disks"`              // Path -> disco creado con mkdisk
+	Reports           map[string]ReportInfo `json:"reports"`            // ID -> reporte generado con rep
+}
+
+var saveMu sync.Mutex
+
+// StateFilePath devuelve la ruta del archivo de estado
+func StateFilePath() string {
+	if path := os.Getenv(StateFileEnv); path != "" {
+		return path
+	}
+	return defaultStateFile
+}
+
+// SaveState escribe las particiones montadas, el catálogo de discos y el registro de
+// reportes en el archivo de estado
+func SaveState() error {
+	// Solo un guardado a la vez, para que no se pisen los archivos temporales
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
+	stateMu.RLock()
+	state := State{MountedPartitions: mountedPartitions, Disks: disks, Reports: reports}
+	data, err := json.MarshalIndent(state, "", "  ")
+	stateMu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("error al codificar el estado: %w", err)
+	}
+
+	path := StateFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("error al crear la carpeta del estado: %w", err)
+	}
+
+	// Escribir primero en un temporal para no dejar el archivo a medias
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("error al escribir el estado: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error al reemplazar el estado: %w", err)
+	}
+
+	return nil
+}
+
+// LoadState lee el archivo de estado. Si no existe devuelve un estado vacío.
+func LoadState() (*State, error) {
+	state := &State{
+		MountedPartitions: make(map[string]MountInfo),
+		Disks:             make(map[string]DiskInfo),
+		Reports:           make(map[string]ReportInfo),
+	}
+
+	data, err := os.ReadFile(StateFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el estado: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("el archivo de estado está corrupto: %w", err)
+	}
+	if state.MountedPartitions == nil {
+		state.MountedPartitions = make(map[string]MountInfo)
+	}
+	if state.Disks == nil {
+		state.Disks = make(map[string]DiskInfo)
+	}
+	if state.Reports == nil {
+		state.Reports = make(map[string]ReportInfo)
+	}
+
+	return state, nil
+}
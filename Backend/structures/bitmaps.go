@@ -2,6 +2,7 @@ package structures
 
 import (
 	"encoding/binary"
+	"errors"
 	"os"
 )
 
@@ -55,50 +56,143 @@ func (sb *SuperBlock) CreateBitMaps(path string) error {
 	return nil
 }
 
+// IsBitmapFree indica si un byte del bitmap representa un espacio libre.
+// CreateBitMaps usa '0' para inodos y 'O' (letra) para bloques; los asignadores escriben '1'.
+func IsBitmapFree(value byte) bool {
+	return value == '0' || value == 'O' || value == 0
+}
+
 // Actualizar Bitmap de inodos
-func (sb *SuperBlock) UpdateBitmapInode(path string) error {
-	// Abrir el archivo
-	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+func (sb *SuperBlock) UpdateBitmapInode(path string, index int32, value byte) error {
+	return writeBitmapByte(path, int64(sb.S_bm_inode_start)+int64(index), value)
+}
+
+// Actualizar Bitmap de bloques
+func (sb *SuperBlock) UpdateBitmapBlock(path string, index int32, value byte) error {
+	return writeBitmapByte(path, int64(sb.S_bm_block_start)+int64(index), value)
+}
+
+// ReadBitmapInode lee el bitmap de inodos completo
+func (sb *SuperBlock) ReadBitmapInode(path string) ([]byte, error) {
+	return readBitmap(path, int64(sb.S_bm_inode_start), sb.S_inodes_count)
+}
+
+// ReadBitmapBlock lee el bitmap de bloques completo
+func (sb *SuperBlock) ReadBitmapBlock(path string) ([]byte, error) {
+	return readBitmap(path, int64(sb.S_bm_block_start), sb.S_blocks_count)
+}
+
+// AllocateInode busca el primer inodo libre, lo marca en el bitmap y actualiza el superbloque
+func (sb *SuperBlock) AllocateInode(path string) (int32, error) {
+	bitmap, err := sb.ReadBitmapInode(path)
 	if err != nil {
-		return err
+		return -1, err
 	}
-	defer file.Close()
 
-	// Mover el puntero del archivo a la posición del bitmap de inodos
-	_, err = file.Seek(int64(sb.S_bm_inode_start)+int64(sb.S_inodes_count), 0)
-	if err != nil {
-		return err
+	index := firstFree(bitmap, 0)
+	if index == -1 {
+		return -1, errors.New("no hay inodos libres en la partición")
+	}
+
+	if err := sb.UpdateBitmapInode(path, index, '1'); err != nil {
+		return -1, err
+	}
+	sb.S_free_inodes_count--
+
+	// S_first_ino apunta al siguiente inodo libre
+	next := firstFree(bitmap, index+1)
+	if next == -1 {
+		next = sb.S_inodes_count
 	}
+	sb.S_first_ino = sb.S_inode_start + next*sb.S_inode_size
 
-	// Escribir el bit en el archivo
-	_, err = file.Write([]byte{'1'})
+	return index, nil
+}
+
+// AllocateBlock busca el primer bloque libre, lo marca en el bitmap y actualiza el superbloque
+func (sb *SuperBlock) AllocateBlock(path string) (int32, error) {
+	bitmap, err := sb.ReadBitmapBlock(path)
 	if err != nil {
+		return -1, err
+	}
+
+	index := firstFree(bitmap, 0)
+	if index == -1 {
+		return -1, errors.New("no hay bloques libres en la partición")
+	}
+
+	if err := sb.UpdateBitmapBlock(path, index, '1'); err != nil {
+		return -1, err
+	}
+	sb.S_free_blocks_count--
+
+	// S_first_blo apunta al siguiente bloque libre
+	next := firstFree(bitmap, index+1)
+	if next == -1 {
+		next = sb.S_blocks_count
+	}
+	sb.S_first_blo = sb.S_block_start + next*sb.S_block_size
+
+	return index, nil
+}
+
+// FreeInode libera un inodo en el bitmap y actualiza el superbloque
+func (sb *SuperBlock) FreeInode(path string, index int32) error {
+	if err := sb.UpdateBitmapInode(path, index, '0'); err != nil {
 		return err
 	}
+	sb.S_free_inodes_count++
+	if offset := sb.S_inode_start + index*sb.S_inode_size; offset < sb.S_first_ino {
+		sb.S_first_ino = offset
+	}
+	return nil
+}
 
+// FreeBlock libera un bloque en el bitmap y actualiza el superbloque
+func (sb *SuperBlock) FreeBlock(path string, index int32) error {
+	if err := sb.UpdateBitmapBlock(path, index, 'O'); err != nil {
+		return err
+	}
+	sb.S_free_blocks_count++
+	if offset := sb.S_block_start + index*sb.S_block_size; offset < sb.S_first_blo {
+		sb.S_first_blo = offset
+	}
 	return nil
 }
 
-// Actualizar Bitmap de bloques
-func (sb *SuperBlock) UpdateBitmapBlock(path string) error {
-	// Abrir el archivo
-	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+// firstFree devuelve el índice del primer byte libre del bitmap a partir de from, o -1
+func firstFree(bitmap []byte, from int32) int32 {
+	for i := from; i < int32(len(bitmap)); i++ {
+		if IsBitmapFree(bitmap[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func readBitmap(path string, offset int64, count int32) ([]byte, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer file.Close()
 
-	// Mover el puntero del archivo a la posición del bitmap de bloques
-	_, err = file.Seek(int64(sb.S_bm_block_start)+int64(sb.S_blocks_count), 0)
-	if err != nil {
-		return err
+	buffer := make([]byte, count)
+	if _, err := file.ReadAt(buffer, offset); err != nil {
+		return nil, err
 	}
+	return buffer, nil
+}
 
-	// Escribir el bit en el archivo
-	_, err = file.Write([]byte{'X'})
+func writeBitmapByte(path string, offset int64, value byte) error {
+	// Abrir el archivo
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
-	return nil
-}
\ No newline at end of file
+	// Escribir el byte en la posición del bitmap
+	_, err = file.WriteAt([]byte{value}, offset)
+	return err
+}
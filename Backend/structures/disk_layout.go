@@ -0,0 +1,104 @@
+package structures
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// DiskPartition resume una partición de un disco sin importar en qué tabla está
+type DiskPartition struct {
+	Name   string // Nombre de la partición
+	Type   byte   // 'P' primaria (también las de GPT), 'E' extendida o 'L' lógica
+	Fit    byte   // Tipo de ajuste: 'B', 'F', 'W'
+	Status byte   // '0' creada, '1' montada
+	Start  int32  // Byte de inicio de la partición
+	Size   int32  // Tamaño de la partición en bytes
+}
+
+// ListPartitions lee el MBR del disco y devuelve todas sus particiones en orden:
+// primarias y extendidas del MBR seguidas de las lógicas de la cadena de EBRs,
+// o las entradas de la tabla GPT si el disco es GPT
+func ListPartitions(path string) (*MBR, []DiskPartition, error) {
+	var mbr MBR
+	if err := mbr.Deserialize(path); err != nil {
+		return nil, nil, err
+	}
+
+	partitions := []DiskPartition{}
+
+	if mbr.IsGPT() {
+		_, entries, err := ReadGPT(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range entries {
+			if entries[i].IsFree() {
+				continue
+			}
+			partitions = append(partitions, DiskPartition{
+				Name:   entries[i].Name(),
+				Type:   GPTPartitionType,
+				Fit:    entries[i].PartFit,
+				Status: entries[i].PartStatus,
+				Start:  entries[i].PartStart,
+				Size:   entries[i].PartSize,
+			})
+		}
+		return &mbr, partitions, nil
+	}
+
+	for _, part := range mbr.Mbr_partitions {
+		if part.Part_size <= 0 {
+			continue
+		}
+		partitions = append(partitions, DiskPartition{
+			Name:   strings.Trim(string(part.Part_name[:]), "\x00 "),
+			Type:   part.Part_type[0],
+			Fit:    part.Part_fit[0],
+			Status: part.Part_status[0],
+			Start:  part.Part_start,
+			Size:   part.Part_size,
+		})
+
+		if part.Part_type[0] != 'E' {
+			continue
+		}
+
+		// Recorrer la cadena de EBRs de la extendida
+		visited := make(map[int32]bool)
+		current := part.Part_start
+		for current != -1 {
+			if visited[current] {
+				return nil, nil, errors.New("la cadena de EBRs tiene un ciclo")
+			}
+			visited[current] = true
+
+			ebr, err := ReadEBR(path, int64(current))
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ebr.IsFree() {
+				partitions = append(partitions, DiskPartition{
+					Name:   strings.Trim(string(ebr.PartName[:]), "\x00 "),
+					Type:   'L',
+					Fit:    ebr.PartFit,
+					Status: ebr.PartMount,
+					Start:  ebr.PartStart,
+					Size:   ebr.PartSize,
+				})
+			}
+			current = ebr.PartNext
+		}
+	}
+
+	return &mbr, partitions, nil
+}
+
+// DataStart devuelve el byte donde inicia el área de datos (donde iría el superbloque)
+func (dp *DiskPartition) DataStart() int32 {
+	if dp.Type == 'L' {
+		return dp.Start + int32(binary.Size(EBR{}))
+	}
+	return dp.Start
+}
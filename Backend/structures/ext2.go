@@ -1,14 +1,26 @@
 package structures
 
 import (
-	utils "backend/common"
-	"strings"
+	"errors"
 	"time"
 )
 
 // Crear users.txt en nuestro sistema de archivos
 func (sb *SuperBlock) CreateUsersFile(path string) error {
 	// ----------- Creamos / -----------
+	// El inodo raíz y su bloque son siempre los primeros libres (0 y 0)
+	rootIndex, err := sb.AllocateInode(path)
+	if err != nil {
+		return err
+	}
+	rootBlockIndex, err := sb.AllocateBlock(path)
+	if err != nil {
+		return err
+	}
+	if rootIndex != 0 || rootBlockIndex != 0 {
+		return errors.New("los bitmaps no están vacíos, no se puede crear la raíz")
+	}
+
 	// Creamos el inodo raíz
 	rootInode := &Inode{
 		I_uid:   1,
@@ -17,290 +29,40 @@ func (sb *SuperBlock) CreateUsersFile(path string) error {
 		I_atime: float32(time.Now().Unix()),
 		I_ctime: float32(time.Now().Unix()),
 		I_mtime: float32(time.Now().Unix()),
-		I_block: [15]int32{sb.S_blocks_count - sb.S_free_blocks_count, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1},
+		I_block: emptyBlockPointers(),
 		I_type:  [1]byte{'0'},
 		I_perm:  [3]byte{'7', '7', '7'},
 	}
+	rootInode.I_block[0] = rootBlockIndex
 
 	// Serializar el inodo raíz
-	err := rootInode.Serialize(path, int64(sb.S_first_ino))
-	if err != nil {
-		return err
-	}
-
-	// Actualizar el bitmap de inodos
-	err = sb.UpdateBitmapInode(path)
+	err = sb.WriteInode(path, rootIndex, rootInode)
 	if err != nil {
 		return err
 	}
 
-	// Actualizar el superbloque correctamente
-	sb.S_free_inodes_count--
-	//sb.S_first_ino += sb.S_inode_size
-
-	// Creamos el bloque del Inodo Raíz
-	rootBlock := &FolderBlock{
-		B_content: [4]FolderContent{
-			{B_name: [12]byte{'.'}, B_inodo: 0},
-			{B_name: [12]byte{'.', '.'}, B_inodo: 0},
-			{B_name: [12]byte{'-'}, B_inodo: -1},
-			{B_name: [12]byte{'-'}, B_inodo: -1},
-		},
-	}
-
-	// Actualizar el bitmap de bloques
-	err = sb.UpdateBitmapBlock(path)
-	if err != nil {
-		return err
-	}
+	// Creamos el bloque del Inodo Raíz, "." y ".." apuntan a la misma raíz
+	rootBlock := newFolderBlock()
+	rootBlock.B_content[0] = FolderContent{B_name: [12]byte{'.'}, B_inodo: rootIndex}
+	rootBlock.B_content[1] = FolderContent{B_name: [12]byte{'.', '.'}, B_inodo: rootIndex}
 
 	// Serializar el bloque de carpeta raíz
-	err = rootBlock.Serialize(path, int64(sb.S_first_blo))
+	err = rootBlock.Serialize(path, int64(sb.S_block_start+rootBlockIndex*sb.S_block_size))
 	if err != nil {
 		return err
 	}
 
-	// Actualizar el superbloque
-	sb.S_free_blocks_count--
-	sb.S_first_blo += sb.S_block_size
-
 	// ----------- Creamos /users.txt -----------
 	usersText := "1,G,root\n1,U,root,root,123\n"
 
-	// Deserializar el inodo raíz
-	err = rootInode.Deserialize(path, int64(sb.S_inode_start+0)) // 0 porque es el inodo raíz
+	// users.txt queda en el inodo 1, que es donde lo buscan login y los reportes
+	usersIndex, err := sb.CreateInode(path, rootIndex, "users.txt", '1', 1, 1, "777", []byte(usersText))
 	if err != nil {
 		return err
 	}
-
-	// Actualizamos el inodo raíz
-	rootInode.I_atime = float32(time.Now().Unix())
-
-	// Serializar el inodo raíz
-	err = rootInode.Serialize(path, int64(sb.S_inode_start+0)) // 0 porque es el inodo raíz
-	if err != nil {
-		return err
+	if usersIndex != 1 {
+		return errors.New("users.txt no quedó en el inodo 1")
 	}
 
-	// Deserializar el bloque de carpeta raíz
-	err = rootBlock.Deserialize(path, int64(sb.S_block_start+0)) // 0 porque es el bloque de carpeta raíz
-	if err != nil {
-		return err
-	}
-
-	// Actualizamos el bloque de carpeta raíz
-	rootBlock.B_content[2] = FolderContent{B_name: [12]byte{'u', 's', 'e', 'r', 's', '.', 't', 'x', 't'}, B_inodo: 1}
-
-	// Serializar el bloque de carpeta raíz
-	err = rootBlock.Serialize(path, int64(sb.S_block_start+0)) // 0 porque es el bloque de carpeta raíz
-	if err != nil {
-		return err
-	}
-
-	// Creamos el inodo users.txt
-	usersInode := &Inode{
-		I_uid:   1,
-		I_gid:   1,
-		I_size:  int32(len(usersText)),
-		I_atime: float32(time.Now().Unix()),
-		I_ctime: float32(time.Now().Unix()),
-		I_mtime: float32(time.Now().Unix()),
-		I_block: [15]int32{1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, // 👉 apunta directamente al bloque 1
-		I_type:  [1]byte{'1'},
-		I_perm:  [3]byte{'7', '7', '7'},
-	}
-	
-
-	// Actualizar el bitmap de inodos
-	err = sb.UpdateBitmapInode(path)
-	if err != nil {
-		return err
-	}
-
-	// Serializar el inodo users.txt
-	err = usersInode.Serialize(path, int64(sb.S_inode_start + sb.S_inode_size)) // Inodo 1
-	if err != nil {
-		return err
-	}
-
-	// Actualizamos el superbloque
-	sb.S_free_inodes_count--
-	//sb.S_first_ino += sb.S_inode_size
-
-	// Creamos el bloque de users.txt
-	usersBlock := &FileBlock{
-		B_content: [64]byte{},
-	}
-	// Copiamos el texto de usuarios en el bloque
-	copy(usersBlock.B_content[:], usersText)
-
-	// Serializar el bloque de users.txt
-	err = usersBlock.Serialize(path, int64(sb.S_block_start + sb.S_block_size)) // bloque 1
-
-
-	if err != nil {
-		return err
-	}
-
-	// Actualizar el bitmap de bloques
-	err = sb.UpdateBitmapBlock(path)
-	if err != nil {
-		return err
-	}
-	sb.S_free_blocks_count--
-	// Actualizamos el superbloque
-	sb.S_free_blocks_count--
-	sb.S_first_blo += sb.S_block_size
-
 	return nil
 }
-
-// createFolderInInode crea una carpeta en un inodo específico
-func (sb *SuperBlock) createFolderInInode(path string, inodeIndex int32, parentsDir []string, destDir string) error {
-	// Crear un nuevo inodo
-	inode := &Inode{}
-	// Deserializar el inodo
-	err := inode.Deserialize(path, int64(sb.S_inode_start+(inodeIndex*sb.S_inode_size)))
-	if err != nil {
-		return err
-	}
-	// Verificar si el inodo es de tipo carpeta
-	if inode.I_type[0] == '1' {
-		return nil
-	}
-
-	// Iterar sobre cada bloque del inodo (apuntadores)
-	for _, blockIndex := range inode.I_block {
-		// Si el bloque no existe, salir
-		if blockIndex == -1 {
-			break
-		}
-
-		// Crear un nuevo bloque de carpeta
-		block := &FolderBlock{}
-
-		// Deserializar el bloque
-		err := block.Deserialize(path, int64(sb.S_block_start+(blockIndex*sb.S_block_size))) // 64 porque es el tamaño de un bloque
-		if err != nil {
-			return err
-		}
-
-		// Iterar sobre cada contenido del bloque, desde el index 2 porque los primeros dos son . y ..
-		for indexContent := 2; indexContent < len(block.B_content); indexContent++ {
-			// Obtener el contenido del bloque
-			content := block.B_content[indexContent]
-
-			// Sí las carpetas padre no están vacías debereamos buscar la carpeta padre más cercana
-			if len(parentsDir) != 0 {
-				//fmt.Println("---------ESTOY  VISITANDO--------")
-
-				// Si el contenido está vacío, salir
-				if content.B_inodo == -1 {
-					break
-				}
-
-				// Obtenemos la carpeta padre más cercana
-				parentDir, err := utils.First(parentsDir)
-				if err != nil {
-					return err
-				}
-
-				// Convertir B_name a string y eliminar los caracteres nulos
-				contentName := strings.Trim(string(content.B_name[:]), "\x00 ")
-				// Convertir parentDir a string y eliminar los caracteres nulos
-				parentDirName := strings.Trim(parentDir, "\x00 ")
-				// Si el nombre del contenido coincide con el nombre de la carpeta padre
-				if strings.EqualFold(contentName, parentDirName) {
-					//fmt.Println("---------LA ENCONTRÉ-------")
-					// Si son las mismas, entonces entramos al inodo que apunta el bloque
-					err := sb.createFolderInInode(path, content.B_inodo, utils.RemoveElement(parentsDir, 0), destDir)
-					if err != nil {
-						return err
-					}
-					return nil
-				}
-			} else {
-				//fmt.Println("---------ESTOY  CREANDO--------")
-
-				// Si el apuntador al inodo está ocupado, continuar con el siguiente
-				if content.B_inodo != -1 {
-					continue
-				}
-
-				// Actualizar el contenido del bloque
-				copy(content.B_name[:], destDir)
-				content.B_inodo = sb.S_inodes_count - sb.S_free_inodes_count
-
-
-				// Actualizar el bloque
-				block.B_content[indexContent] = content
-
-				// Serializar el bloque
-				err = block.Serialize(path, int64(sb.S_block_start+(blockIndex*sb.S_block_size)))
-				if err != nil {
-					return err
-				}
-
-				// Crear el inodo de la carpeta
-				folderInode := &Inode{
-					I_uid:   1,
-					I_gid:   1,
-					I_size:  0,
-					I_atime: float32(time.Now().Unix()),
-					I_ctime: float32(time.Now().Unix()),
-					I_mtime: float32(time.Now().Unix()),
-					I_block: [15]int32{sb.S_blocks_count - sb.S_free_blocks_count, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1},
-					I_type:  [1]byte{'0'},
-					I_perm:  [3]byte{'6', '6', '4'},
-				}
-
-				// Serializar el inodo de la carpeta
-				err = folderInode.Serialize(path, int64(sb.S_first_ino))
-				if err != nil {
-					return err
-				}
-
-				// Actualizar el bitmap de inodos
-				err = sb.UpdateBitmapInode(path)
-				if err != nil {
-					return err
-				}
-
-				// Actualizar el superbloque correctamente
-				sb.S_free_inodes_count--
-				//sb.S_first_ino += sb.S_inode_size
-
-				// Crear el bloque de la carpeta
-				folderBlock := &FolderBlock{
-					B_content: [4]FolderContent{
-						{B_name: [12]byte{'.'}, B_inodo: content.B_inodo},
-						{B_name: [12]byte{'.', '.'}, B_inodo: inodeIndex},
-						{B_name: [12]byte{'-'}, B_inodo: -1},
-						{B_name: [12]byte{'-'}, B_inodo: -1},
-					},
-				}
-
-				// Serializar el bloque de la carpeta
-				err = folderBlock.Serialize(path, int64(sb.S_first_blo))
-				if err != nil {
-					return err
-				}
-
-				// Actualizar el bitmap de bloques
-				err = sb.UpdateBitmapBlock(path)
-				if err != nil {
-					return err
-				}
-
-				// Actualizar el superbloque
-				sb.S_free_blocks_count--
-				sb.S_first_blo += sb.S_block_size
-				
-
-				return nil
-			}
-		}
-
-	}
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,397 @@
+package structures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cantidad de apuntadores que caben en un PointerBlock
+const PointersPerBlock = 16
+
+// Largo máximo de un nombre dentro de un FolderBlock
+const MaxNameLength = 12
+
+// ErrNameTooLong se devuelve cuando un nombre no cabe en B_name
+var ErrNameTooLong = fmt.Errorf("el nombre no puede tener más de %d caracteres", MaxNameLength)
+
+// WriteInode escribe el inodo en la posición que le corresponde a su índice
+func (sb *SuperBlock) WriteInode(path string, index int32, inode *Inode) error {
+	return inode.Serialize(path, int64(sb.S_inode_start+index*sb.S_inode_size))
+}
+
+// FindChild busca una entrada por nombre dentro de una carpeta
+func (sb *SuperBlock) FindChild(path string, dir *Inode, name string) (int32, bool, error) {
+	entries, err := sb.ListDirectory(path, dir)
+	if err != nil {
+		return -1, false, err
+	}
+	for _, entry := range entries {
+		if entry.Name == name {
+			return entry.Inode, true, nil
+		}
+	}
+	return -1, false, nil
+}
+
+// CreateInode crea un archivo ('1') o carpeta ('0') dentro de la carpeta parentIndex y devuelve su índice.
+// Si algo falla se liberan el inodo y los bloques que alcanzó a ocupar.
+func (sb *SuperBlock) CreateInode(path string, parentIndex int32, name string, typ byte, uid, gid int32, perm string, content []byte) (int32, error) {
+	if len(name) > MaxNameLength {
+		return -1, ErrNameTooLong
+	}
+
+	index, err := sb.AllocateInode(path)
+	if err != nil {
+		return -1, err
+	}
+
+	now := float32(time.Now().Unix())
+	inode := &Inode{
+		I_uid:   uid,
+		I_gid:   gid,
+		I_size:  0,
+		I_atime: now,
+		I_ctime: now,
+		I_mtime: now,
+		I_block: emptyBlockPointers(),
+		I_type:  [1]byte{typ},
+	}
+	copy(inode.I_perm[:], perm)
+
+	if typ == '0' {
+		// Toda carpeta inicia con un bloque que contiene "." y ".."
+		blockIndex, err := sb.AllocateBlock(path)
+		if err != nil {
+			return -1, sb.discardInode(path, index, inode, err)
+		}
+		inode.I_block[0] = blockIndex
+
+		folderBlock := newFolderBlock()
+		folderBlock.B_content[0] = FolderContent{B_name: [12]byte{'.'}, B_inodo: index}
+		folderBlock.B_content[1] = FolderContent{B_name: [12]byte{'.', '.'}, B_inodo: parentIndex}
+		if err := folderBlock.Serialize(path, int64(sb.S_block_start+blockIndex*sb.S_block_size)); err != nil {
+			return -1, sb.discardInode(path, index, inode, err)
+		}
+		if err := sb.WriteInode(path, index, inode); err != nil {
+			return -1, sb.discardInode(path, index, inode, err)
+		}
+	} else if err := sb.WriteFileContent(path, index, inode, content); err != nil {
+		return -1, sb.discardInode(path, index, inode, err)
+	}
+
+	if err := sb.AddDirEntry(path, parentIndex, name, index); err != nil {
+		return -1, sb.discardInode(path, index, inode, err)
+	}
+
+	return index, nil
+}
+
+// discardInode libera un inodo a medio crear junto con sus bloques y devuelve cause
+// acompañado de los errores de la liberación, si los hubo
+func (sb *SuperBlock) discardInode(path string, index int32, inode *Inode, cause error) error {
+	return errors.Join(cause, sb.FreeInodeBlocks(path, inode), sb.FreeInode(path, index))
+}
+
+// WriteFileContent reemplaza el contenido del archivo index: escribe el contenido en
+// bloques nuevos, guarda el inodo apuntando a ellos y solo entonces libera los bloques
+// anteriores. Si algo falla antes de guardar el inodo, se liberan los bloques nuevos y el
+// archivo queda como estaba.
+func (sb *SuperBlock) WriteFileContent(path string, index int32, inode *Inode, content []byte) error {
+	staged := &Inode{I_block: emptyBlockPointers()}
+	if err := sb.writeContentBlocks(path, staged, content); err != nil {
+		return errors.Join(err, sb.FreeInodeBlocks(path, staged))
+	}
+
+	previous := *inode
+	inode.I_block = staged.I_block
+	inode.I_size = int32(len(content))
+	inode.I_mtime = float32(time.Now().Unix())
+	if err := sb.WriteInode(path, index, inode); err != nil {
+		*inode = previous
+		return errors.Join(err, sb.FreeInodeBlocks(path, staged))
+	}
+
+	return sb.FreeInodeBlocks(path, &previous)
+}
+
+// writeContentBlocks escribe content en bloques nuevos asignados a inode; si falla,
+// los bloques que ya quedaron asignados siguen en inode para poder liberarlos
+func (sb *SuperBlock) writeContentBlocks(path string, inode *Inode, content []byte) error {
+	blockSize := int(sb.S_block_size)
+	for n := 0; n*blockSize < len(content); n++ {
+		blockIndex, err := sb.AllocateBlock(path)
+		if err != nil {
+			return err
+		}
+
+		end := (n + 1) * blockSize
+		if end > len(content) {
+			end = len(content)
+		}
+		block := &FileBlock{}
+		copy(block.B_content[:], content[n*blockSize:end])
+		if err := block.Serialize(path, int64(sb.S_block_start+blockIndex*sb.S_block_size)); err != nil {
+			return errors.Join(err, sb.FreeBlock(path, blockIndex))
+		}
+
+		if err := sb.assignBlock(path, inode, n, blockIndex); err != nil {
+			return errors.Join(err, sb.FreeBlock(path, blockIndex))
+		}
+	}
+	return nil
+}
+
+// AddDirEntry agrega la entrada name -> child en la carpeta parentIndex,
+// usando un espacio libre o un bloque nuevo si la carpeta está llena
+func (sb *SuperBlock) AddDirEntry(path string, parentIndex int32, name string, child int32) error {
+	if len(name) > MaxNameLength {
+		return ErrNameTooLong
+	}
+
+	parent, err := sb.ReadInode(path, parentIndex)
+	if err != nil {
+		return err
+	}
+
+	entry := FolderContent{B_inodo: child}
+	copy(entry.B_name[:], name)
+
+	blocks, err := sb.InodeBlocks(path, parent)
+	if err != nil {
+		return err
+	}
+	for _, blockIndex := range blocks {
+		offset := int64(sb.S_block_start + blockIndex*sb.S_block_size)
+		block := &FolderBlock{}
+		if err := block.Deserialize(path, offset); err != nil {
+			return err
+		}
+		for i := range block.B_content {
+			if block.B_content[i].B_inodo != -1 {
+				continue
+			}
+			block.B_content[i] = entry
+			if err := block.Serialize(path, offset); err != nil {
+				return err
+			}
+			return sb.touchInode(path, parentIndex, parent)
+		}
+	}
+
+	// No hay espacio, la carpeta necesita un bloque nuevo
+	blockIndex, err := sb.AllocateBlock(path)
+	if err != nil {
+		return err
+	}
+	block := newFolderBlock()
+	block.B_content[0] = entry
+	if err := block.Serialize(path, int64(sb.S_block_start+blockIndex*sb.S_block_size)); err != nil {
+		return errors.Join(err, sb.FreeBlock(path, blockIndex))
+	}
+	if err := sb.assignBlock(path, parent, len(blocks), blockIndex); err != nil {
+		return errors.Join(err, sb.FreeBlock(path, blockIndex))
+	}
+	return sb.touchInode(path, parentIndex, parent)
+}
+
+// RemoveDirEntry quita la entrada con el nombre indicado de la carpeta parentIndex
+func (sb *SuperBlock) RemoveDirEntry(path string, parentIndex int32, name string) error {
+	return sb.updateDirEntry(path, parentIndex, name, func(entry *FolderContent) {
+		*entry = FolderContent{B_name: [12]byte{'-'}, B_inodo: -1}
+	})
+}
+
+// RenameDirEntry cambia el nombre de una entrada de la carpeta parentIndex
+func (sb *SuperBlock) RenameDirEntry(path string, parentIndex int32, oldName, newName string) error {
+	if len(newName) > MaxNameLength {
+		return ErrNameTooLong
+	}
+	return sb.updateDirEntry(path, parentIndex, oldName, func(entry *FolderContent) {
+		entry.B_name = [12]byte{}
+		copy(entry.B_name[:], newName)
+	})
+}
+
+// RemoveInode libera un inodo con todos sus bloques; si es carpeta, libera también su contenido
+func (sb *SuperBlock) RemoveInode(path string, index int32) error {
+	inode, err := sb.ReadInode(path, index)
+	if err != nil {
+		return err
+	}
+
+	if inode.I_type[0] == '0' {
+		children, err := sb.ListDirectory(path, inode)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if err := sb.RemoveInode(path, child.Inode); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := sb.FreeInodeBlocks(path, inode); err != nil {
+		return err
+	}
+	return sb.FreeInode(path, index)
+}
+
+// FreeInodeBlocks libera todos los bloques de datos y de apuntadores del inodo
+func (sb *SuperBlock) FreeInodeBlocks(path string, inode *Inode) error {
+	for i := 0; i < DirectBlocks; i++ {
+		if inode.I_block[i] == -1 {
+			continue
+		}
+		if err := sb.FreeBlock(path, inode.I_block[i]); err != nil {
+			return err
+		}
+	}
+	for level := 1; level <= 3; level++ {
+		pointer := inode.I_block[DirectBlocks+level-1]
+		if pointer == -1 {
+			continue
+		}
+		if err := sb.freeIndirect(path, pointer, level); err != nil {
+			return err
+		}
+	}
+	inode.I_block = emptyBlockPointers()
+	inode.I_size = 0
+	return nil
+}
+
+// assignBlock coloca blockIndex como el bloque número n del inodo, creando los
+// bloques de apuntadores indirectos que hagan falta
+func (sb *SuperBlock) assignBlock(path string, inode *Inode, n int, blockIndex int32) error {
+	if n < DirectBlocks {
+		inode.I_block[n] = blockIndex
+		return nil
+	}
+
+	n -= DirectBlocks
+	capacity := 1
+	for level := 1; level <= 3; level++ {
+		capacity *= PointersPerBlock
+		if n < capacity {
+			return sb.assignIndirect(path, &inode.I_block[DirectBlocks+level-1], level, n, blockIndex)
+		}
+		n -= capacity
+	}
+	return errors.New("el archivo excede el tamaño máximo soportado por el inodo")
+}
+
+// assignIndirect coloca blockIndex en el bloque de apuntadores *slot de nivel level. Si el
+// bloque de apuntadores se crea aquí y algo falla, se libera y *slot vuelve a -1.
+func (sb *SuperBlock) assignIndirect(path string, slot *int32, level int, n int, blockIndex int32) (err error) {
+	pointerBlock := &PointerBlock{}
+	if *slot == -1 {
+		newIndex, allocErr := sb.AllocateBlock(path)
+		if allocErr != nil {
+			return allocErr
+		}
+		*slot = newIndex
+		for i := range pointerBlock.P_pointers {
+			pointerBlock.P_pointers[i] = -1
+		}
+		defer func() {
+			if err != nil {
+				err = errors.Join(err, sb.FreeBlock(path, newIndex))
+				*slot = -1
+			}
+		}()
+	} else if err := pointerBlock.Deserialize(path, int64(sb.S_block_start+*slot*sb.S_block_size)); err != nil {
+		return err
+	}
+
+	if level == 1 {
+		pointerBlock.P_pointers[n] = blockIndex
+	} else {
+		span := 1
+		for i := 1; i < level; i++ {
+			span *= PointersPerBlock
+		}
+		if err := sb.assignIndirect(path, &pointerBlock.P_pointers[n/span], level-1, n%span, blockIndex); err != nil {
+			return err
+		}
+	}
+
+	return pointerBlock.Serialize(path, int64(sb.S_block_start+*slot*sb.S_block_size))
+}
+
+func (sb *SuperBlock) freeIndirect(path string, pointerIndex int32, level int) error {
+	pointerBlock := &PointerBlock{}
+	if err := pointerBlock.Deserialize(path, int64(sb.S_block_start+pointerIndex*sb.S_block_size)); err != nil {
+		return err
+	}
+	for _, pointer := range pointerBlock.P_pointers {
+		if pointer == -1 {
+			continue
+		}
+		var err error
+		if level == 1 {
+			err = sb.FreeBlock(path, pointer)
+		} else {
+			err = sb.freeIndirect(path, pointer, level-1)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return sb.FreeBlock(path, pointerIndex)
+}
+
+// updateDirEntry aplica update a la entrada name de la carpeta parentIndex
+func (sb *SuperBlock) updateDirEntry(path string, parentIndex int32, name string, update func(*FolderContent)) error {
+	parent, err := sb.ReadInode(path, parentIndex)
+	if err != nil {
+		return err
+	}
+	blocks, err := sb.InodeBlocks(path, parent)
+	if err != nil {
+		return err
+	}
+
+	for _, blockIndex := range blocks {
+		offset := int64(sb.S_block_start + blockIndex*sb.S_block_size)
+		block := &FolderBlock{}
+		if err := block.Deserialize(path, offset); err != nil {
+			return err
+		}
+		for i := range block.B_content {
+			entryName := strings.Trim(string(block.B_content[i].B_name[:]), "\x00 ")
+			if block.B_content[i].B_inodo == -1 || entryName != name || entryName == "." || entryName == ".." {
+				continue
+			}
+			update(&block.B_content[i])
+			if err := block.Serialize(path, offset); err != nil {
+				return err
+			}
+			return sb.touchInode(path, parentIndex, parent)
+		}
+	}
+	return fmt.Errorf("no existe '%s' en la carpeta", name)
+}
+
+// touchInode actualiza la fecha de modificación del inodo y lo escribe
+func (sb *SuperBlock) touchInode(path string, index int32, inode *Inode) error {
+	inode.I_mtime = float32(time.Now().Unix())
+	return sb.WriteInode(path, index, inode)
+}
+
+func emptyBlockPointers() [15]int32 {
+	return [15]int32{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}
+}
+
+func newFolderBlock() *FolderBlock {
+	return &FolderBlock{
+		B_content: [4]FolderContent{
+			{B_name: [12]byte{'-'}, B_inodo: -1},
+			{B_name: [12]byte{'-'}, B_inodo: -1},
+			{B_name: [12]byte{'-'}, B_inodo: -1},
+			{B_name: [12]byte{'-'}, B_inodo: -1},
+		},
+	}
+}
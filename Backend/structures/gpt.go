@@ -0,0 +1,317 @@
+package structures
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+)
+
+// Firma que identifica un encabezado GPT
+const GPTSignature = "EFI PART"
+
+// Cantidad máxima de entradas en la tabla de particiones GPT
+const GPTMaxEntries = 128
+
+// Tipo de la entrada del MBR protector que cubre todo el disco GPT
+const GPTProtectiveType = 'G'
+
+// Tipo con el que se listan las particiones de la tabla GPT: GPT no tiene extendidas
+// ni lógicas, todas sus particiones son primarias
+const GPTPartitionType = 'P'
+
+// GUID del tipo "Linux filesystem data" usado para todas las particiones que creamos
+var GPTLinuxDataType = [16]byte{0xaf, 0x3d, 0xc6, 0x0f, 0x83, 0x84, 0x72, 0x47, 0x8e, 0x79, 0x3d, 0x69, 0xd8, 0x47, 0x7d, 0xe4}
+
+// GPTHeader es el encabezado de la tabla GPT, se escribe justo después del MBR protector
+type GPTHeader struct {
+	Signature     [8]byte  // "EFI PART"
+	Revision      int32    // Revisión del formato
+	HeaderSize    int32    // Tamaño del encabezado en bytes
+	HeaderCRC32   uint32   // CRC32 del encabezado (calculado con este campo en 0)
+	CurrentOffset int32    // Byte donde está este encabezado
+	FirstUsable   int32    // Primer byte disponible para particiones
+	LastUsable    int32    // Último byte disponible para particiones
+	DiskGUID      [16]byte // GUID del disco
+	EntriesStart  int32    // Byte donde inicia la tabla de entradas
+	NumEntries    int32    // Cantidad de entradas de la tabla
+	EntrySize     int32    // Tamaño de cada entrada en bytes
+	EntriesCRC32  uint32   // CRC32 de toda la tabla de entradas
+	// Total: 64 bytes
+}
+
+// GPTEntry representa una partición dentro de la tabla GPT
+type GPTEntry struct {
+	TypeGUID        [16]byte // GUID del tipo de partición (ceros = entrada libre)
+	UniqueGUID      [16]byte // GUID único de la partición
+	PartStart       int32    // Byte de inicio de la partición
+	PartSize        int32    // Tamaño de la partición en bytes
+	PartStatus      byte     // Estado: '0' (creada) o '1' (montada)
+	PartFit         byte     // Tipo de ajuste: 'B', 'F', 'W'
+	PartName        [16]byte // Nombre de la partición
+	PartCorrelative int32    // Correlativo de montaje
+	PartId          [4]byte  // ID de montaje
+	// Total: 66 bytes
+}
+
+// IsGPT indica si el MBR es el protector de un disco GPT
+func (mbr *MBR) IsGPT() bool {
+	return mbr.Mbr_partitions[0].Part_type[0] == GPTProtectiveType
+}
+
+// NewGPT crea el encabezado y la tabla vacía para un disco del tamaño indicado; falla si
+// el disco no alcanza para el encabezado, la tabla y al menos un byte de datos
+func NewGPT(diskSize int32) (*GPTHeader, []GPTEntry, error) {
+	headerStart := int32(binary.Size(MBR{}))
+	headerSize := int32(binary.Size(GPTHeader{}))
+	entrySize := int32(binary.Size(GPTEntry{}))
+	entriesStart := headerStart + headerSize
+
+	diskGUID, err := NewGUID()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := &GPTHeader{
+		Revision:      0x00010000,
+		HeaderSize:    headerSize,
+		CurrentOffset: headerStart,
+		FirstUsable:   entriesStart + entrySize*GPTMaxEntries,
+		LastUsable:    diskSize - 1,
+		DiskGUID:      diskGUID,
+		EntriesStart:  entriesStart,
+		NumEntries:    GPTMaxEntries,
+		EntrySize:     entrySize,
+	}
+	copy(header.Signature[:], GPTSignature)
+
+	if header.FirstUsable > header.LastUsable {
+		return nil, nil, fmt.Errorf("el disco es muy pequeño para una tabla GPT: se necesitan más de %d bytes", header.FirstUsable)
+	}
+
+	return header, make([]GPTEntry, GPTMaxEntries), nil
+}
+
+// NewProtectiveMBRPartition crea la entrada del MBR que protege todo el disco GPT
+func NewProtectiveMBRPartition(diskSize int32) Partition {
+	start := int32(binary.Size(MBR{}))
+	partition := Partition{
+		Part_status:      [1]byte{'0'},
+		Part_type:        [1]byte{GPTProtectiveType},
+		Part_fit:         [1]byte{'N'},
+		Part_start:       start,
+		Part_size:        diskSize - start,
+		Part_correlative: -1,
+		Part_id:          [4]byte{'N'},
+	}
+	copy(partition.Part_name[:], "GPT")
+	return partition
+}
+
+// WriteGPT escribe el encabezado y la tabla de entradas, recalculando los CRC32
+func WriteGPT(path string, header *GPTHeader, entries []GPTEntry) error {
+	if len(entries) != int(header.NumEntries) {
+		return fmt.Errorf("la tabla GPT debe tener %d entradas", header.NumEntries)
+	}
+
+	var entriesBuf bytes.Buffer
+	if err := binary.Write(&entriesBuf, binary.LittleEndian, entries); err != nil {
+		return err
+	}
+	header.EntriesCRC32 = crc32.ChecksumIEEE(entriesBuf.Bytes())
+
+	header.HeaderCRC32 = 0
+	headerCRC, err := header.checksum()
+	if err != nil {
+		return err
+	}
+	header.HeaderCRC32 = headerCRC
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(int64(header.CurrentOffset), 0); err != nil {
+		return err
+	}
+	if err := binary.Write(file, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(int64(header.EntriesStart), 0); err != nil {
+		return err
+	}
+	_, err = file.Write(entriesBuf.Bytes())
+	return err
+}
+
+// ReadGPT lee el encabezado y la tabla de entradas validando la firma y los CRC32
+func ReadGPT(path string) (*GPTHeader, []GPTEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	header := &GPTHeader{}
+	if _, err := file.Seek(int64(binary.Size(MBR{})), 0); err != nil {
+		return nil, nil, err
+	}
+	if err := binary.Read(file, binary.LittleEndian, header); err != nil {
+		return nil, nil, err
+	}
+
+	if string(header.Signature[:]) != GPTSignature {
+		return nil, nil, errors.New("el disco no tiene un encabezado GPT válido")
+	}
+
+	storedCRC := header.HeaderCRC32
+	header.HeaderCRC32 = 0
+	headerCRC, err := header.checksum()
+	if err != nil {
+		return nil, nil, err
+	}
+	header.HeaderCRC32 = storedCRC
+	if headerCRC != storedCRC {
+		return nil, nil, errors.New("el CRC32 del encabezado GPT no coincide")
+	}
+
+	// Los campos vienen del disco: se validan antes de usarlos para reservar memoria
+	if header.NumEntries <= 0 || header.NumEntries > GPTMaxEntries {
+		return nil, nil, fmt.Errorf("cantidad de entradas GPT inválida: %d", header.NumEntries)
+	}
+	if header.EntrySize != int32(binary.Size(GPTEntry{})) {
+		return nil, nil, fmt.Errorf("tamaño de entrada GPT inválido: %d", header.EntrySize)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	entriesEnd := int64(header.EntriesStart) + int64(header.NumEntries)*int64(header.EntrySize)
+	if header.EntriesStart < 0 || entriesEnd > info.Size() {
+		return nil, nil, fmt.Errorf("la tabla de particiones GPT está fuera del disco: bytes %d a %d", header.EntriesStart, entriesEnd)
+	}
+	if header.FirstUsable > header.LastUsable {
+		return nil, nil, fmt.Errorf("el área utilizable de la tabla GPT es inválida: %d > %d", header.FirstUsable, header.LastUsable)
+	}
+
+	raw := make([]byte, header.NumEntries*header.EntrySize)
+	if _, err := file.Seek(int64(header.EntriesStart), 0); err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.ReadFull(file, raw); err != nil {
+		return nil, nil, err
+	}
+	if crc32.ChecksumIEEE(raw) != header.EntriesCRC32 {
+		return nil, nil, errors.New("el CRC32 de la tabla de particiones GPT no coincide")
+	}
+
+	entries := make([]GPTEntry, header.NumEntries)
+	if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, entries); err != nil {
+		return nil, nil, err
+	}
+
+	return header, entries, nil
+}
+
+// checksum calcula el CRC32 del encabezado tal como está en memoria
+func (header *GPTHeader) checksum() (uint32, error) {
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return 0, err
+	}
+	return crc32.ChecksumIEEE(buf.Bytes()), nil
+}
+
+// GetGPTPartitionByName busca una entrada de la tabla GPT por nombre y devuelve su índice
+func GetGPTPartitionByName(entries []GPTEntry, name string) (*GPTEntry, int) {
+	inputName := strings.Trim(name, "\x00 ")
+	for i := range entries {
+		if entries[i].IsFree() {
+			continue
+		}
+		if strings.EqualFold(entries[i].Name(), inputName) {
+			return &entries[i], i
+		}
+	}
+	return nil, -1
+}
+
+// IsFree indica si la entrada no tiene partición
+func (entry *GPTEntry) IsFree() bool {
+	return entry.TypeGUID == [16]byte{}
+}
+
+// Name devuelve el nombre de la partición sin caracteres nulos
+func (entry *GPTEntry) Name() string {
+	return strings.Trim(string(entry.PartName[:]), "\x00 ")
+}
+
+// CreatePartition llena la entrada con una nueva partición; si no se puede generar su
+// GUID la entrada queda sin cambios
+func (entry *GPTEntry) CreatePartition(partStart, partSize int, partFit byte, partName string) error {
+	guid, err := NewGUID()
+	if err != nil {
+		return err
+	}
+	entry.TypeGUID = GPTLinuxDataType
+	entry.UniqueGUID = guid
+	entry.PartStart = int32(partStart)
+	entry.PartSize = int32(partSize)
+	entry.PartStatus = '0'
+	entry.PartFit = partFit
+	entry.PartName = [16]byte{}
+	copy(entry.PartName[:], partName)
+	entry.PartCorrelative = -1
+	entry.PartId = [4]byte{'N'}
+	return nil
+}
+
+// MountPartition marca la entrada como montada con el correlativo e ID indicados
+func (entry *GPTEntry) MountPartition(correlative int, id string) {
+	entry.PartStatus = '1'
+	entry.PartCorrelative = int32(correlative)
+	entry.PartId = [4]byte{}
+	copy(entry.PartId[:], id)
+}
+
+// UnmountPartition regresa la entrada a su estado sin montar
+func (entry *GPTEntry) UnmountPartition() {
+	entry.PartStatus = '0'
+	entry.PartCorrelative = -1
+	entry.PartId = [4]byte{'N'}
+}
+
+// NewMountedPartitionFromGPTEntry crea el descriptor de una partición GPT
+func NewMountedPartitionFromGPTEntry(entry *GPTEntry) *MountedPartition {
+	return &MountedPartition{
+		Start: entry.PartStart,
+		Size:  entry.PartSize,
+		Fit:   entry.PartFit,
+		Name:  entry.Name(),
+		Kind:  'G',
+	}
+}
+
+// NewGUID genera un GUID aleatorio (versión 4)
+func NewGUID() ([16]byte, error) {
+	var guid [16]byte
+	if _, err := rand.Read(guid[:]); err != nil {
+		return guid, fmt.Errorf("no se pudo generar un GUID: %w", err)
+	}
+	guid[6] = (guid[6] & 0x0f) | 0x40 // versión 4
+	guid[8] = (guid[8] & 0x3f) | 0x80 // variante RFC 4122
+	return guid, nil
+}
+
+// GUIDString convierte un GUID al formato xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func GUIDString(guid [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", guid[0:4], guid[4:6], guid[6:8], guid[8:10], guid[10:16])
+}
@@ -0,0 +1,100 @@
+package structures
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newGPTDisk crea un disco vacío del tamaño indicado con su encabezado y tabla GPT
+func newGPTDisk(t *testing.T, size int32) (string, *GPTHeader, []GPTEntry) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gpt.mia")
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	header, entries, err := NewGPT(size)
+	if err != nil {
+		t.Fatalf("NewGPT: %v", err)
+	}
+	return path, header, entries
+}
+
+func TestGPTRoundTrip(t *testing.T) {
+	path, header, entries := newGPTDisk(t, 64*1024)
+	if err := entries[0].CreatePartition(int(header.FirstUsable), 4096, 'F', "Particion1"); err != nil {
+		t.Fatal(err)
+	}
+	entries[0].MountPartition(1, "781A")
+	if err := WriteGPT(path, header, entries); err != nil {
+		t.Fatalf("WriteGPT: %v", err)
+	}
+
+	readHeader, readEntries, err := ReadGPT(path)
+	if err != nil {
+		t.Fatalf("ReadGPT: %v", err)
+	}
+	if *readHeader != *header {
+		t.Errorf("encabezado = %+v, se esperaba %+v", readHeader, header)
+	}
+	if len(readEntries) != GPTMaxEntries {
+		t.Fatalf("%d entradas, se esperaban %d", len(readEntries), GPTMaxEntries)
+	}
+	if readEntries[0] != entries[0] {
+		t.Errorf("entrada = %+v, se esperaba %+v", readEntries[0], entries[0])
+	}
+	if entry, i := GetGPTPartitionByName(readEntries, "particion1"); entry == nil || i != 0 {
+		t.Errorf("no se encontró Particion1 en la tabla leída")
+	}
+	if !readEntries[1].IsFree() {
+		t.Errorf("la entrada 1 debía estar libre")
+	}
+}
+
+func TestReadGPTRejectsCorruption(t *testing.T) {
+	tests := []struct {
+		desc   string
+		offset func(header *GPTHeader) int64
+	}{
+		// Un byte del GUID del disco cambia el CRC32 del encabezado
+		{"encabezado", func(header *GPTHeader) int64 { return int64(header.CurrentOffset) + 32 }},
+		// Un byte del nombre de la primera entrada cambia el CRC32 de la tabla
+		{"tabla", func(header *GPTHeader) int64 { return int64(header.EntriesStart) + 42 }},
+	}
+
+	for _, tt := range tests {
+		path, header, entries := newGPTDisk(t, 64*1024)
+		if err := entries[0].CreatePartition(int(header.FirstUsable), 4096, 'F', "Particion1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteGPT(path, header, entries); err != nil {
+			t.Fatalf("WriteGPT: %v", err)
+		}
+
+		file, err := os.OpenFile(path, os.O_RDWR, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := make([]byte, 1)
+		offset := tt.offset(header)
+		if _, err := file.ReadAt(b, offset); err != nil {
+			t.Fatal(err)
+		}
+		b[0] ^= 0xff
+		if _, err := file.WriteAt(b, offset); err != nil {
+			t.Fatal(err)
+		}
+		file.Close()
+
+		if _, _, err := ReadGPT(path); err == nil || !strings.Contains(err.Error(), "CRC32") {
+			t.Errorf("%s corrupto: ReadGPT devolvió %v, se esperaba un error de CRC32", tt.desc, err)
+		}
+	}
+}
+
+func TestNewGPTRejectsSmallDisk(t *testing.T) {
+	if _, _, err := NewGPT(1024); err == nil {
+		t.Error("NewGPT debía fallar con un disco de 1024 bytes")
+	}
+}
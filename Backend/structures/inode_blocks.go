@@ -0,0 +1,210 @@
+package structures
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Cantidad de apuntadores directos en I_block (los índices 12, 13 y 14 son indirectos)
+const DirectBlocks = 12
+
+// DirEntry es una entrada de una carpeta: nombre e inodo al que apunta
+type DirEntry struct {
+	Name  string
+	Inode int32
+}
+
+// InodeBlocks devuelve, en orden, los índices de los bloques de datos del inodo,
+// siguiendo los apuntadores indirectos simple, doble y triple
+func (sb *SuperBlock) InodeBlocks(path string, inode *Inode) ([]int32, error) {
+	var blocks []int32
+
+	for i := 0; i < DirectBlocks; i++ {
+		if inode.I_block[i] != -1 {
+			blocks = append(blocks, inode.I_block[i])
+		}
+	}
+
+	for level := 1; level <= 3; level++ {
+		pointer := inode.I_block[DirectBlocks+level-1]
+		if pointer == -1 {
+			continue
+		}
+		indirect, err := sb.indirectBlocks(path, pointer, level)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, indirect...)
+	}
+
+	return blocks, nil
+}
+
+// indirectBlocks recorre un bloque de apuntadores del nivel indicado y devuelve los bloques de datos
+func (sb *SuperBlock) indirectBlocks(path string, pointerIndex int32, level int) ([]int32, error) {
+	pointerBlock := &PointerBlock{}
+	err := pointerBlock.Deserialize(path, int64(sb.S_block_start+pointerIndex*sb.S_block_size))
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []int32
+	for _, pointer := range pointerBlock.P_pointers {
+		if pointer == -1 {
+			continue
+		}
+		if level == 1 {
+			blocks = append(blocks, pointer)
+			continue
+		}
+		nested, err := sb.indirectBlocks(path, pointer, level-1)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, nested...)
+	}
+	return blocks, nil
+}
+
+// BlockKind tipo real de un bloque, según el inodo que lo referencia
+type BlockKind string
+
+const (
+	BlockFolder  BlockKind = "carpeta"
+	BlockFile    BlockKind = "archivo"
+	BlockPointer BlockKind = "apuntadores"
+)
+
+// BlockRef es un bloque referenciado por un inodo con su tipo y nivel de indirección
+// (0 para bloques de datos; 1, 2 o 3 para bloques de apuntadores)
+type BlockRef struct {
+	Index int32
+	Kind  BlockKind
+	Level int
+}
+
+// InodeBlockRefs devuelve todos los bloques del inodo, incluidos los de apuntadores,
+// en el orden en que se recorren
+func (sb *SuperBlock) InodeBlockRefs(path string, inode *Inode) ([]BlockRef, error) {
+	dataKind := BlockFile
+	if inode.I_type[0] == '0' {
+		dataKind = BlockFolder
+	}
+
+	var refs []BlockRef
+	for i := 0; i < DirectBlocks; i++ {
+		if inode.I_block[i] != -1 {
+			refs = append(refs, BlockRef{Index: inode.I_block[i], Kind: dataKind})
+		}
+	}
+
+	for level := 1; level <= 3; level++ {
+		pointer := inode.I_block[DirectBlocks+level-1]
+		if pointer == -1 {
+			continue
+		}
+		nested, err := sb.indirectBlockRefs(path, pointer, level, dataKind)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, nested...)
+	}
+
+	return refs, nil
+}
+
+// indirectBlockRefs devuelve el bloque de apuntadores y todos los bloques que cuelgan de él
+func (sb *SuperBlock) indirectBlockRefs(path string, pointerIndex int32, level int, dataKind BlockKind) ([]BlockRef, error) {
+	pointerBlock := &PointerBlock{}
+	err := pointerBlock.Deserialize(path, int64(sb.S_block_start+pointerIndex*sb.S_block_size))
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []BlockRef{{Index: pointerIndex, Kind: BlockPointer, Level: level}}
+	for _, pointer := range pointerBlock.P_pointers {
+		if pointer == -1 {
+			continue
+		}
+		if level == 1 {
+			refs = append(refs, BlockRef{Index: pointer, Kind: dataKind})
+			continue
+		}
+		nested, err := sb.indirectBlockRefs(path, pointer, level-1, dataKind)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, nested...)
+	}
+	return refs, nil
+}
+
+// ReadInode lee el inodo con el índice indicado
+func (sb *SuperBlock) ReadInode(path string, index int32) (*Inode, error) {
+	if index < 0 || index >= sb.S_inodes_count {
+		return nil, fmt.Errorf("inodo fuera de rango: %d", index)
+	}
+	inode := &Inode{}
+	err := inode.Deserialize(path, int64(sb.S_inode_start+index*sb.S_inode_size))
+	if err != nil {
+		return nil, err
+	}
+	return inode, nil
+}
+
+// ReadFile devuelve el contenido de un archivo leyendo todos sus bloques y respetando I_size
+func (sb *SuperBlock) ReadFile(path string, inode *Inode) ([]byte, error) {
+	if inode.I_type[0] != '1' {
+		return nil, fmt.Errorf("el inodo no corresponde a un archivo")
+	}
+
+	blocks, err := sb.InodeBlocks(path, inode)
+	if err != nil {
+		return nil, err
+	}
+
+	content := make([]byte, 0, len(blocks)*int(sb.S_block_size))
+	for _, blockIndex := range blocks {
+		block := &FileBlock{}
+		err := block.Deserialize(path, int64(sb.S_block_start+blockIndex*sb.S_block_size))
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, block.B_content[:]...)
+	}
+
+	if int(inode.I_size) < len(content) {
+		content = content[:inode.I_size]
+	}
+	return content, nil
+}
+
+// ListDirectory devuelve las entradas de una carpeta, sin incluir "." ni ".."
+func (sb *SuperBlock) ListDirectory(path string, inode *Inode) ([]DirEntry, error) {
+	if inode.I_type[0] != '0' {
+		return nil, fmt.Errorf("el inodo no corresponde a una carpeta")
+	}
+
+	blocks, err := sb.InodeBlocks(path, inode)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []DirEntry
+	for _, blockIndex := range blocks {
+		block := &FolderBlock{}
+		err := block.Deserialize(path, int64(sb.S_block_start+blockIndex*sb.S_block_size))
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range block.B_content {
+			name := strings.TrimRight(string(bytes.Trim(content.B_name[:], "\x00")), " ")
+			if content.B_inodo == -1 || name == "" || name == "." || name == ".." {
+				continue
+			}
+			entries = append(entries, DirEntry{Name: name, Inode: content.B_inodo})
+		}
+	}
+	return entries, nil
+}
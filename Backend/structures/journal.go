@@ -0,0 +1,160 @@
+package structures
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Information es una operación registrada en el journal de una partición EXT3
+type Information struct {
+	I_operation [10]byte
+	I_path      [32]byte
+	I_content   [64]byte
+	I_date      float32
+	// Total: 110 bytes
+}
+
+type Journal struct {
+	J_count   int32
+	J_content Information
+	// Total: 114 bytes
+}
+
+// Serialize escribe la estructura Journal en un archivo binario en la posición especificada
+func (j *Journal) Serialize(path string, offset int64) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Mover el puntero del archivo a la posición especificada
+	_, err = file.Seek(offset, 0)
+	if err != nil {
+		return err
+	}
+
+	// Serializar la estructura Journal directamente en el archivo
+	return binary.Write(file, binary.LittleEndian, j)
+}
+
+// Deserialize lee la estructura Journal desde un archivo binario en la posición especificada
+func (j *Journal) Deserialize(path string, offset int64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Mover el puntero del archivo a la posición especificada
+	_, err = file.Seek(offset, 0)
+	if err != nil {
+		return err
+	}
+
+	// Leer solo la cantidad de bytes que corresponden al tamaño de la estructura Journal
+	jSize := binary.Size(j)
+	if jSize <= 0 {
+		return fmt.Errorf("invalid Journal size: %d", jSize)
+	}
+	buffer := make([]byte, jSize)
+	_, err = file.Read(buffer)
+	if err != nil {
+		return err
+	}
+
+	// Deserializar los bytes leídos en la estructura Journal
+	reader := bytes.NewReader(buffer)
+	return binary.Read(reader, binary.LittleEndian, j)
+}
+
+// IsEmpty indica si la entrada todavía no registra ninguna operación
+func (j *Journal) IsEmpty() bool {
+	return j.J_content.I_operation[0] == 0
+}
+
+// Operation, Path y Content devuelven los campos de la entrada como texto
+func (j *Journal) Operation() string { return journalString(j.J_content.I_operation[:]) }
+func (j *Journal) Path() string      { return journalString(j.J_content.I_path[:]) }
+func (j *Journal) Content() string   { return journalString(j.J_content.I_content[:]) }
+
+func journalString(b []byte) string {
+	return strings.TrimRight(string(bytes.TrimRight(b, "\x00")), " ")
+}
+
+// ReadJournal lee, en orden, las entradas del journal de una partición EXT3. El journal
+// ocupa el espacio entre el superbloque y el bitmap de inodos; la lectura termina en la
+// primera entrada vacía.
+func (sb *SuperBlock) ReadJournal(path string, partitionStart int32) ([]Journal, error) {
+	if sb.S_filesystem_type != 3 {
+		return nil, fmt.Errorf("la partición es EXT%d y solo las particiones EXT3 tienen journal", sb.S_filesystem_type)
+	}
+
+	journalStart, count := sb.journalArea(partitionStart)
+	journalSize := int32(binary.Size(Journal{}))
+
+	var entries []Journal
+	for i := int32(0); i < count; i++ {
+		entry := Journal{}
+		if err := entry.Deserialize(path, int64(journalStart+i*journalSize)); err != nil {
+			return nil, err
+		}
+		if entry.IsEmpty() {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// journalArea devuelve dónde empieza el journal y cuántas entradas caben en él
+func (sb *SuperBlock) journalArea(partitionStart int32) (int32, int32) {
+	journalStart := partitionStart + int32(binary.Size(SuperBlock{}))
+	return journalStart, (sb.S_bm_inode_start - journalStart) / int32(binary.Size(Journal{}))
+}
+
+// CreateJournal deja vacío el espacio del journal; lo llama mkfs al formatear con EXT3
+func (sb *SuperBlock) CreateJournal(path string, partitionStart int32) error {
+	journalStart, count := sb.journalArea(partitionStart)
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	buffer := make([]byte, int(count)*binary.Size(Journal{}))
+	if _, err := file.WriteAt(buffer, int64(journalStart)); err != nil {
+		return fmt.Errorf("error al inicializar el journal: %w", err)
+	}
+	return nil
+}
+
+// AppendJournal registra una operación en la primera entrada libre del journal. En
+// particiones EXT2 no hace nada; el contenido se recorta a lo que cabe en la entrada.
+func (sb *SuperBlock) AppendJournal(path string, partitionStart int32, operation string, target string, content string) error {
+	if sb.S_filesystem_type != 3 {
+		return nil
+	}
+
+	entries, err := sb.ReadJournal(path, partitionStart)
+	if err != nil {
+		return err
+	}
+	journalStart, count := sb.journalArea(partitionStart)
+	if int32(len(entries)) >= count {
+		return fmt.Errorf("el journal está lleno (%d entradas)", count)
+	}
+
+	entry := Journal{J_count: int32(len(entries)) + 1}
+	copy(entry.J_content.I_operation[:], operation)
+	copy(entry.J_content.I_path[:], target)
+	copy(entry.J_content.I_content[:], content)
+	entry.J_content.I_date = float32(time.Now().Unix())
+
+	return entry.Serialize(path, int64(journalStart+int32(len(entries))*int32(binary.Size(Journal{}))))
+}
@@ -89,6 +89,10 @@ func (mbr *MBR) GetFirstAvailablePartition() (*Partition, int, int) {
 func (mbr *MBR) GetPartitionByName(name string) (*Partition, int) {
 	// Recorrer las particiones del MBR
 	for i, partition := range mbr.Mbr_partitions {
+		// La entrada protectora de un disco GPT no es una partición real
+		if partition.Part_type[0] == GPTProtectiveType {
+			continue
+		}
 		// Convertir Part_name a string y eliminar los caracteres nulos
 		partitionName := strings.Trim(string(partition.Part_name[:]), "\x00 ")
 		// Convertir el nombre de la partición a string y eliminar los caracteres nulos
@@ -159,7 +163,7 @@ func (mbr *MBR) PrintPartitions() {
 
 func (mbr *MBR) GetLogicalPartitionByName(name string, path string) (EBR, error) {
 	for _, part := range mbr.Mbr_partitions {
-		if part.Part_type[0] == 'E' && part.Part_status[0] == '1' {
+		if part.Part_type[0] == 'E' {
 			current := part.Part_start
 			for current != -1 {
 				var ebr EBR
@@ -0,0 +1,84 @@
+package structures
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// MountedPartition describe el área de datos de una partición montable,
+// sin importar si viene de una entrada del MBR (primaria), de un EBR (lógica) o de la tabla GPT
+type MountedPartition struct {
+	Start int32  // Byte donde inicia el área de datos (donde va el superbloque)
+	Size  int32  // Tamaño del área de datos en bytes
+	Fit   byte   // Tipo de ajuste: 'B', 'F', 'W'
+	Name  string // Nombre de la partición
+	Kind  byte   // Tipo de partición: 'P' (primaria), 'L' (lógica) o 'G' (entrada GPT)
+}
+
+// NewMountedPartitionFromPartition crea el descriptor de una partición primaria del MBR
+func NewMountedPartitionFromPartition(p *Partition) *MountedPartition {
+	return &MountedPartition{
+		Start: p.Part_start,
+		Size:  p.Part_size,
+		Fit:   p.Part_fit[0],
+		Name:  strings.Trim(string(p.Part_name[:]), "\x00 "),
+		Kind:  'P',
+	}
+}
+
+// NewMountedPartitionFromEBR crea el descriptor de una partición lógica.
+// El EBR ocupa los primeros bytes de la partición, así que los datos inician después de él.
+func NewMountedPartitionFromEBR(ebr *EBR) *MountedPartition {
+	ebrSize := int32(binary.Size(EBR{}))
+	return &MountedPartition{
+		Start: ebr.PartStart + ebrSize,
+		Size:  ebr.PartSize - ebrSize,
+		Fit:   ebr.PartFit,
+		Name:  strings.Trim(string(ebr.PartName[:]), "\x00 "),
+		Kind:  'L',
+	}
+}
+
+// FindMountablePartition busca una partición primaria, lógica o GPT por nombre y devuelve su descriptor
+func (mbr *MBR) FindMountablePartition(name string, path string) (*MountedPartition, error) {
+	if mbr.IsGPT() {
+		_, entries, err := ReadGPT(path)
+		if err != nil {
+			return nil, err
+		}
+		entry, _ := GetGPTPartitionByName(entries, name)
+		if entry == nil {
+			return nil, fmt.Errorf("la partición '%s' no existe en la tabla GPT", name)
+		}
+		return NewMountedPartitionFromGPTEntry(entry), nil
+	}
+
+	partition, _ := mbr.GetPartitionByName(name)
+	if partition != nil {
+		if partition.Part_type[0] == 'E' {
+			return nil, fmt.Errorf("la partición '%s' es extendida", name)
+		}
+		return NewMountedPartitionFromPartition(partition), nil
+	}
+
+	ebr, err := mbr.GetLogicalPartitionByName(name, path)
+	if err != nil {
+		return nil, fmt.Errorf("la partición '%s' no existe (ni primaria ni lógica)", name)
+	}
+	return NewMountedPartitionFromEBR(&ebr), nil
+}
+
+// IsLogical indica si la partición es lógica
+func (mp *MountedPartition) IsLogical() bool {
+	return mp.Kind == 'L'
+}
+
+// Print imprime los valores del descriptor
+func (mp *MountedPartition) Print() {
+	fmt.Printf("Name: %s\n", mp.Name)
+	fmt.Printf("Kind: %c\n", mp.Kind)
+	fmt.Printf("Fit: %c\n", mp.Fit)
+	fmt.Printf("Start: %d\n", mp.Start)
+	fmt.Printf("Size: %d\n", mp.Size)
+}
@@ -61,6 +61,18 @@ func (p *Partition) MountPartition(correlative int, id string) error {
 	return nil
 }
 
+// Desmontar la partición, regresando el status, correlativo e ID a sus valores iniciales
+func (p *Partition) UnmountPartition() {
+	// El valor '0' indica que la partición existe pero no está montada
+	p.Part_status[0] = '0'
+
+	// Reiniciar el correlativo
+	p.Part_correlative = -1
+
+	// Reiniciar el ID tal como lo deja mkdisk
+	p.Part_id = [4]byte{'N'}
+}
+
 // Imprimir los valores de la partición
 func (p *Partition) PrintPartition() {
 	fmt.Printf("Part_status: %c\n", p.Part_status[0])
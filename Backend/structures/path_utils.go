@@ -20,10 +20,9 @@ func FindInodeByPath(diskPath string, absolutePath string, sb SuperBlock) (int32
 
 	for _, part := range parts {
 		// Leer el inodo actual
-		inode := Inode{}
-		err := inode.Deserialize(diskPath, int64(sb.S_inode_start+(currentInodeIndex*sb.S_inode_size)))
+		inode, err := sb.ReadInode(diskPath, currentInodeIndex)
 		if err != nil {
-			return -1, errors.New("Error al deserializar inodo en ruta")
+			return -1, errors.New("error al deserializar inodo en ruta")
 		}
 
 		// Verificar si es carpeta
@@ -31,30 +30,17 @@ func FindInodeByPath(diskPath string, absolutePath string, sb SuperBlock) (int32
 			return -1, errors.New("Ruta intermedia no es una carpeta")
 		}
 
-		found := false
-
-		// Buscar el nombre dentro de los bloques de carpeta
-		for _, blockIndex := range inode.I_block {
-			if blockIndex == -1 {
-				continue
-			}
-
-			block := FolderBlock{}
-			err := block.Deserialize(diskPath, int64(sb.S_block_start+blockIndex*sb.S_block_size))
-			if err != nil {
-				return -1, errors.New("Error al leer bloque de carpeta")
-			}
-
-			for _, entry := range block.B_content {
-				entryName := strings.Trim(string(entry.B_name[:]), "\x00")
-				if entryName == part && entry.B_inodo != -1 {
-					currentInodeIndex = entry.B_inodo
-					found = true
-					break
-				}
-			}
+		// Buscar el nombre dentro de los bloques de carpeta (directos e indirectos)
+		entries, err := sb.ListDirectory(diskPath, inode)
+		if err != nil {
+			return -1, errors.New("error al leer bloque de carpeta")
+		}
 
-			if found {
+		found := false
+		for _, entry := range entries {
+			if entry.Name == part {
+				currentInodeIndex = entry.Inode
+				found = true
 				break
 			}
 		}
@@ -3,6 +3,7 @@ package structures
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -90,6 +91,16 @@ func (sb *SuperBlock) Deserialize(path string, offset int64) error {
 	return nil
 }
 
+// DetectFilesystem devuelve el sistema de archivos ("EXT2" o "EXT3") del superbloque en la posición indicada,
+// o "" si la partición no ha sido formateada
+func DetectFilesystem(path string, offset int64) string {
+	var sb SuperBlock
+	if err := sb.Deserialize(path, offset); err != nil || sb.S_magic != 0xEF53 {
+		return ""
+	}
+	return fmt.Sprintf("EXT%d", sb.S_filesystem_type)
+}
+
 // PrintSuperBlock imprime los valores de la estructura SuperBlock
 func (sb *SuperBlock) Print() {
 	// Convertir el tiempo de montaje a una fecha
@@ -187,21 +198,81 @@ func (sb *SuperBlock) PrintBlocks(path string) error {
 	return nil
 }
 
-// CreateFolder crea una carpeta en el sistema de archivos
-func (sb *SuperBlock) CreateFolder(path string, parentsDir []string, destDir string, allowParents bool) error {
-	// Si parentsDir está vacío, solo trabajar con el primer inodo que sería el raíz "/"
-	if len(parentsDir) == 0 {
-		return sb.createFolderInInode(path, 0, parentsDir, destDir)
+// CreateFolder crea una carpeta en el sistema de archivos recorriendo parentsDir desde la raíz.
+// Si allowParents es true crea con los mismos dueños las carpetas padre que no existan.
+// Antes de crear nada se valida toda la ruta; si aun así falla una creación, se eliminan
+// las carpetas padre que se alcanzaron a crear.
+func (sb *SuperBlock) CreateFolder(path string, parentsDir []string, destDir string, allowParents bool, uid int32, gid int32) error {
+	for _, dirName := range append(parentsDir[:len(parentsDir):len(parentsDir)], destDir) {
+		if len(dirName) > MaxNameLength {
+			return ErrNameTooLong
+		}
 	}
 
-	// Iterar sobre cada inodo ya que se necesita buscar el inodo padre
-	for i := int32(0); i < sb.S_inodes_count; i++ {
-		err := sb.createFolderInInode(path, i, parentsDir, destDir)
+	// Recorrer la parte de la ruta que ya existe
+	currentIndex := int32(0) // Inodo raíz
+	existing := 0
+	for _, dirName := range parentsDir {
+		current, err := sb.ReadInode(path, currentIndex)
+		if err != nil {
+			return err
+		}
+		childIndex, found, err := sb.FindChild(path, current, dirName)
+		if err != nil {
+			return err
+		}
+		if !found {
+			break
+		}
+
+		child, err := sb.ReadInode(path, childIndex)
+		if err != nil {
+			return err
+		}
+		if child.I_type[0] != '0' {
+			return fmt.Errorf("'%s' no es una carpeta", dirName)
+		}
+		currentIndex = childIndex
+		existing++
+	}
+
+	missing := parentsDir[existing:]
+	if len(missing) > 0 && !allowParents {
+		return fmt.Errorf("la carpeta padre '%s' no existe", missing[0])
+	}
+	if len(missing) == 0 {
+		parent, err := sb.ReadInode(path, currentIndex)
 		if err != nil {
 			return err
 		}
+		if _, found, err := sb.FindChild(path, parent, destDir); err != nil {
+			return err
+		} else if found {
+			return fmt.Errorf("ya existe '%s'", destDir)
+		}
+	}
+
+	// Cada carpeta nueva ocupa un inodo y un bloque
+	folders := append(missing[:len(missing):len(missing)], destDir)
+	if sb.S_free_inodes_count < int32(len(folders)) || sb.S_free_blocks_count < int32(len(folders)) {
+		return fmt.Errorf("no hay espacio para crear %d carpetas en la partición", len(folders))
 	}
 
+	anchorIndex := currentIndex
+	firstCreated := int32(-1)
+	for _, dirName := range folders {
+		childIndex, err := sb.CreateInode(path, currentIndex, dirName, '0', uid, gid, "664", nil)
+		if err != nil {
+			if firstCreated != -1 {
+				err = errors.Join(err, sb.RemoveDirEntry(path, anchorIndex, folders[0]), sb.RemoveInode(path, firstCreated))
+			}
+			return err
+		}
+		if firstCreated == -1 {
+			firstCreated = childIndex
+		}
+		currentIndex = childIndex
+	}
 	return nil
 }
 
@@ -232,6 +303,29 @@ func (sb *SuperBlock) GetUsersBlock(path string) (*FileBlock, error) {
 
 
 
+// ReadUsersFile devuelve el contenido completo de users.txt (inodo 1)
+func (sb *SuperBlock) ReadUsersFile(path string) (string, error) {
+	inode, err := sb.ReadInode(path, 1)
+	if err != nil {
+		return "", err
+	}
+	content, err := sb.ReadFile(path, inode)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// WriteUsersFile reemplaza el contenido de users.txt (inodo 1), usando tantos bloques como necesite.
+// El superbloque debe serializarse después porque cambian los contadores de bloques libres.
+func (sb *SuperBlock) WriteUsersFile(path string, content string) error {
+	inode, err := sb.ReadInode(path, 1)
+	if err != nil {
+		return err
+	}
+	return sb.WriteFileContent(path, 1, inode, []byte(content))
+}
+
 // Verifica si todas las carpetas en dirNames existen en el sistema de archivos
 func (sb *SuperBlock) DirectoriesExist(diskPath string, dirNames []string) bool {
 	currentInode := int32(0) // Inodo raíz
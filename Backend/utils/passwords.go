@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Prefijo de las contraseñas guardadas como hash en users.txt:
+// pbkdf2-sha256$<iteraciones>$<sal>$<hash>, con sal y hash en base64 sin relleno
+const passwordHashPrefix = "pbkdf2-sha256"
+
+const (
+	passwordIterations = 10000
+	passwordSaltSize   = 16
+	passwordKeySize    = sha256.Size
+)
+
+// HashPassword genera el hash con sal de una contraseña, listo para guardarse en users.txt
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error al generar la sal: %w", err)
+	}
+
+	key := pbkdf2SHA256([]byte(password), salt, passwordIterations, passwordKeySize)
+	return strings.Join([]string{
+		passwordHashPrefix,
+		strconv.Itoa(passwordIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// IsPasswordHash indica si el valor guardado ya es un hash y no una contraseña en texto plano
+func IsPasswordHash(stored string) bool {
+	return strings.HasPrefix(stored, passwordHashPrefix+"$")
+}
+
+// VerifyPassword compara la contraseña con lo guardado en users.txt, distinguiendo mayúsculas
+// y en tiempo constante. Acepta entradas antiguas en texto plano para poder migrarlas.
+func VerifyPassword(stored string, password string) bool {
+	if !IsPasswordHash(stored) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	}
+
+	parts := strings.Split(stored, "$")
+	if len(parts) != 4 {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	key := pbkdf2SHA256([]byte(password), salt, iterations, len(expected))
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+// pbkdf2SHA256 implementa PBKDF2 (RFC 8018) con HMAC-SHA256
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, keyLen)
+
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		var counter [4]byte
+		binary.BigEndian.PutUint32(counter[:], block)
+		prf.Write(counter[:])
+		u := prf.Sum(nil)
+
+		t := make([]byte, len(u))
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
+
+// SetUserPassword reemplaza la contraseña del usuario en el contenido de users.txt,
+// conservando el resto de líneas tal como están
+func SetUserPassword(content string, username string, password string) (string, error) {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		fields := strings.Split(line, ",")
+		if len(fields) != 5 {
+			continue
+		}
+		for j := range fields {
+			fields[j] = strings.Trim(fields[j], "\x00 \r")
+		}
+		if fields[1] != "U" || fields[0] == "0" || fields[3] != username {
+			continue
+		}
+		fields[4] = password
+		lines[i] = strings.Join(fields, ",")
+		return strings.Join(lines, "\n"), nil
+	}
+	return "", fmt.Errorf("el usuario %s no existe", username)
+}
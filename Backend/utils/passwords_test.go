@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyPassword(t *testing.T) {
+	hashed, err := HashPassword("Secreto123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsPasswordHash(hashed) {
+		t.Fatalf("HashPassword devolvió %q, que no es un hash", hashed)
+	}
+
+	tests := []struct {
+		stored   string
+		password string
+		want     bool
+	}{
+		{hashed, "Secreto123", true},
+		{hashed, "secreto123", false},
+		{hashed, "SECRETO123", false},
+		{hashed, "", false},
+		// Entradas antiguas en texto plano, también distinguen mayúsculas
+		{"123", "123", true},
+		{"Abc", "abc", false},
+		{"123", "1234", false},
+		// Hash dañado
+		{"pbkdf2-sha256$10000$sinhash", "Secreto123", false},
+		{"pbkdf2-sha256$0$AAAA$AAAA", "Secreto123", false},
+	}
+	for _, tt := range tests {
+		if got := VerifyPassword(tt.stored, tt.password); got != tt.want {
+			t.Errorf("VerifyPassword(%q, %q) = %v, se esperaba %v", tt.stored, tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestHashPasswordUsesSalt(t *testing.T) {
+	first, err := HashPassword("123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := HashPassword("123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Error("dos hashes de la misma contraseña no deben ser iguales")
+	}
+}
+
+// TestMigratePlaintextPassword sigue el camino de login con una entrada en texto plano:
+// se verifica tal cual y se reemplaza por su hash sin tocar las demás líneas
+func TestMigratePlaintextPassword(t *testing.T) {
+	content := "1,G,root\n1,U,root,root,123\n2,G,usuarios\n2,U,usuarios,user1,abc\n"
+
+	if IsPasswordHash("123") || !VerifyPassword("123", "123") {
+		t.Fatal("la contraseña en texto plano debía verificarse sin ser hash")
+	}
+	hashed, err := HashPassword("123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated, err := SetUserPassword(content, "root", hashed)
+	if err != nil {
+		t.Fatalf("SetUserPassword: %v", err)
+	}
+
+	want := strings.Replace(content, "1,U,root,root,123", "1,U,root,root,"+hashed, 1)
+	if migrated != want {
+		t.Errorf("users.txt migrado:\n%s\nse esperaba:\n%s", migrated, want)
+	}
+	if strings.Contains(migrated, ",123\n") {
+		t.Error("la contraseña en texto plano sigue en users.txt")
+	}
+
+	if _, err := SetUserPassword(content, "nadie", hashed); err == nil {
+		t.Error("SetUserPassword debía fallar con un usuario que no existe")
+	}
+}
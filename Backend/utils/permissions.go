@@ -6,24 +6,25 @@ package utils
 import (
 	"backend/stores"
 	"backend/structures"
+	"strings"
 )
 
 
-// HasWritePermission verifica si el usuario actual tiene permiso de escritura en el inodo
-func HasWritePermission(inode structures.Inode) bool {
-	username, _, _ := stores.Auth.GetCurrentUser()
+// HasWritePermission verifica si el usuario de la sesión tiene permiso de escritura en el inodo
+func HasWritePermission(session *stores.AuthStore, inode structures.Inode) bool {
+	info := session.Info()
 
 	// root siempre tiene todos los permisos
-	if username == "root" {
+	if info.Username == "root" {
 		return true
 	}
 
 	perm := string(inode.I_perm[:]) // Ej: "764"
 
 	var accessChar byte
-	if int(inode.I_uid) == stores.Auth.UserID {
+	if int(inode.I_uid) == info.UserID {
 		accessChar = perm[0] // Usuario propietario
-	} else if int(inode.I_gid) == stores.Auth.GroupID {
+	} else if int(inode.I_gid) == info.GroupID {
 		accessChar = perm[1] // Grupo
 	} else {
 		accessChar = perm[2] // Otros
@@ -32,4 +33,55 @@ func HasWritePermission(inode structures.Inode) bool {
 	// Convertimos el caracter a permiso numérico y validamos bit de escritura
 	// 0 = ---  (000), 1 = --x (001), 2 = -w- (010), 3 = -wx (011), ...
 	return accessChar == '2' || accessChar == '3' || accessChar == '6' || accessChar == '7'
-}
\ No newline at end of file
+}
+
+// HasReadPermission verifica si el usuario de la sesión tiene permiso de lectura en el inodo
+func HasReadPermission(session *stores.AuthStore, inode structures.Inode) bool {
+	info := session.Info()
+
+	// root siempre tiene todos los permisos
+	if info.Username == "root" {
+		return true
+	}
+
+	perm := string(inode.I_perm[:]) // Ej: "764"
+
+	var accessChar byte
+	if int(inode.I_uid) == info.UserID {
+		accessChar = perm[0] // Usuario propietario
+	} else if int(inode.I_gid) == info.GroupID {
+		accessChar = perm[1] // Grupo
+	} else {
+		accessChar = perm[2] // Otros
+	}
+
+	// 4 = r-- (100), 5 = r-x (101), 6 = rw- (110), 7 = rwx (111)
+	return accessChar == '4' || accessChar == '5' || accessChar == '6' || accessChar == '7'
+}
+
+// PermissionString convierte los permisos del inodo al formato de ls, ej: "drwxrwxr-x" o "-rw-rw-r--"
+func PermissionString(inode structures.Inode) string {
+	var result strings.Builder
+
+	if inode.I_type[0] == '0' {
+		result.WriteByte('d')
+	} else {
+		result.WriteByte('-')
+	}
+
+	for _, digit := range inode.I_perm {
+		value := digit - '0'
+		if digit < '0' || digit > '7' {
+			value = 0
+		}
+		for i, letter := range "rwx" {
+			if value&(4>>i) != 0 {
+				result.WriteRune(letter)
+			} else {
+				result.WriteByte('-')
+			}
+		}
+	}
+
+	return result.String()
+}
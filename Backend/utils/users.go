@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Registro de un grupo en users.txt: GID, G, nombre
+type GroupRecord struct {
+	GID  int
+	Name string
+}
+
+// Registro de un usuario en users.txt: UID, U, grupo, usuario, contraseña
+type UserRecord struct {
+	UID      int
+	Group    string
+	Username string
+	Password string
+}
+
+// UsersFile es el contenido de users.txt ya separado en grupos y usuarios
+type UsersFile struct {
+	Groups []GroupRecord
+	Users  []UserRecord
+}
+
+// ParseUsersFile interpreta el contenido de users.txt. Las líneas mal formadas se ignoran.
+func ParseUsersFile(content string) *UsersFile {
+	users := &UsersFile{}
+
+	for _, line := range strings.Split(strings.Trim(content, "\x00"), "\n") {
+		fields := strings.Split(line, ",")
+		for i := range fields {
+			fields[i] = strings.Trim(fields[i], "\x00 \r")
+		}
+		if len(fields) < 3 {
+			continue
+		}
+
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case fields[1] == "G" && len(fields) == 3:
+			users.Groups = append(users.Groups, GroupRecord{GID: id, Name: fields[2]})
+		case fields[1] == "U" && len(fields) == 5:
+			users.Users = append(users.Users, UserRecord{UID: id, Group: fields[2], Username: fields[3], Password: fields[4]})
+		}
+	}
+
+	return users
+}
+
+// FindUser busca un usuario activo (UID distinto de 0) por nombre
+func (u *UsersFile) FindUser(username string) *UserRecord {
+	for i := range u.Users {
+		if u.Users[i].UID != 0 && u.Users[i].Username == username {
+			return &u.Users[i]
+		}
+	}
+	return nil
+}
+
+// GroupID devuelve el GID de un grupo activo, o -1 si no existe
+func (u *UsersFile) GroupID(name string) int {
+	for _, group := range u.Groups {
+		if group.GID != 0 && group.Name == name {
+			return group.GID
+		}
+	}
+	return -1
+}
+
+// UserName devuelve el nombre del usuario con el UID indicado, o el número si no existe
+func (u *UsersFile) UserName(uid int) string {
+	for _, user := range u.Users {
+		if user.UID != 0 && user.UID == uid {
+			return user.Username
+		}
+	}
+	return strconv.Itoa(uid)
+}
+
+// GroupName devuelve el nombre del grupo con el GID indicado, o el número si no existe
+func (u *UsersFile) GroupName(gid int) string {
+	for _, group := range u.Groups {
+		if group.GID != 0 && group.GID == gid {
+			return group.Name
+		}
+	}
+	return strconv.Itoa(gid)
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"unsafe"
 )
 
@@ -35,33 +36,96 @@ var alphabet = []string{
 // Mapa para almacenar la asignación de letras a los diferentes paths
 var pathToLetter = make(map[string]string)
 
-// Mapa para almacenar el contador de particiones por path
-var pathToPartitionCount = make(map[string]int)
+// Mapa para almacenar los correlativos en uso por path
+var pathToCorrelatives = make(map[string]map[int]bool)
 
-// Índice para la siguiente letra disponible en el abecedario
-var nextLetterIndex = 0
+// lettersMu protege pathToLetter y pathToCorrelatives
+var lettersMu sync.Mutex
 
 // GetLetter obtiene la letra asignada a un path y el siguiente índice de partición
 func GetLetterAndPartitionCorrelative(path string) (string, int, error) {
+	lettersMu.Lock()
+	defer lettersMu.Unlock()
+
 	// Asignar una letra al path si no tiene una asignada
 	if _, exists := pathToLetter[path]; !exists {
-		if nextLetterIndex < len(alphabet) {
-			pathToLetter[path] = alphabet[nextLetterIndex]
-			pathToPartitionCount[path] = 0 // Inicializar el contador de particiones
-			nextLetterIndex++
-		} else {
+		letter, err := nextFreeLetter()
+		if err != nil {
 			fmt.Println("Error: no hay más letras disponibles para asignar")
-			return "", 0, errors.New("no hay más letras disponibles para asignar")
+			return "", 0, err
 		}
+		pathToLetter[path] = letter
+		pathToCorrelatives[path] = make(map[int]bool) // Inicializar los correlativos del path
 	}
 
-	// Incrementar y obtener el siguiente índice de partición para este path
-	pathToPartitionCount[path]++
-	nextIndex := pathToPartitionCount[path]
+	// Buscar el menor correlativo libre para este path (reutiliza los liberados por unmount)
+	nextIndex := 1
+	for pathToCorrelatives[path][nextIndex] {
+		nextIndex++
+	}
+	pathToCorrelatives[path][nextIndex] = true
 
 	return pathToLetter[path], nextIndex, nil
 }
 
+// ReleasePartitionCorrelative libera el correlativo de una partición desmontada.
+// Si el disco ya no tiene particiones montadas, su letra queda disponible otra vez.
+func ReleasePartitionCorrelative(path string, correlative int) {
+	lettersMu.Lock()
+	defer lettersMu.Unlock()
+
+	correlatives, exists := pathToCorrelatives[path]
+	if !exists {
+		return
+	}
+
+	delete(correlatives, correlative)
+	if len(correlatives) == 0 {
+		delete(pathToCorrelatives, path)
+		delete(pathToLetter, path)
+	}
+}
+
+// RegisterPartitionCorrelative reserva una letra y correlativo ya asignados previamente,
+// por ejemplo al recuperar las particiones montadas desde el archivo de estado
+func RegisterPartitionCorrelative(path string, letter string, correlative int) error {
+	lettersMu.Lock()
+	defer lettersMu.Unlock()
+
+	if assigned, exists := pathToLetter[path]; exists && assigned != letter {
+		return fmt.Errorf("el disco %s ya tiene asignada la letra %s", path, assigned)
+	}
+	for otherPath, assigned := range pathToLetter {
+		if otherPath != path && assigned == letter {
+			return fmt.Errorf("la letra %s ya está asignada al disco %s", letter, otherPath)
+		}
+	}
+	if pathToCorrelatives[path][correlative] {
+		return fmt.Errorf("el correlativo %d ya está en uso en el disco %s", correlative, path)
+	}
+
+	if _, exists := pathToLetter[path]; !exists {
+		pathToLetter[path] = letter
+		pathToCorrelatives[path] = make(map[int]bool)
+	}
+	pathToCorrelatives[path][correlative] = true
+	return nil
+}
+
+// nextFreeLetter devuelve la primera letra del abecedario que no esté asignada a ningún disco; se llama con lettersMu tomado
+func nextFreeLetter() (string, error) {
+	used := make(map[string]bool)
+	for _, letter := range pathToLetter {
+		used[letter] = true
+	}
+	for _, letter := range alphabet {
+		if !used[letter] {
+			return letter, nil
+		}
+	}
+	return "", errors.New("no hay más letras disponibles para asignar")
+}
+
 // createParentDirs crea las carpetas padre si no existen
 func CreateParentDirs(path string) error {
 	dir := filepath.Dir(path)
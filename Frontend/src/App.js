@@ -21,6 +21,61 @@ function App() {
     }
   }, [output]);
 
+  // Autocompletado de comandos y parámetros con la metadata de GET /commands
+  const handleEditorMount = (editor, monaco) => {
+    fetch("http://localhost:3001/commands")
+      .then((res) => res.json())
+      .then((commands) => {
+        monaco.languages.registerCompletionItemProvider("plaintext", {
+          triggerCharacters: ["-"],
+          provideCompletionItems: (model, position) => {
+            const line = model.getLineContent(position.lineNumber).slice(0, position.column - 1);
+            const word = model.getWordUntilPosition(position);
+            const range = {
+              startLineNumber: position.lineNumber,
+              endLineNumber: position.lineNumber,
+              startColumn: word.startColumn,
+              endColumn: word.endColumn,
+            };
+
+            // Primera palabra de la línea: nombre del comando
+            if (!line.trim().includes(" ")) {
+              return {
+                suggestions: commands.map((cmd) => ({
+                  label: cmd.name,
+                  kind: monaco.languages.CompletionItemKind.Function,
+                  detail: cmd.usage,
+                  documentation: cmd.description,
+                  insertText: cmd.name,
+                  range,
+                })),
+              };
+            }
+
+            // Resto de la línea: parámetros del comando
+            const cmd = commands.find((c) => c.name === line.trim().split(/\s+/)[0].toLowerCase());
+            if (!cmd) return { suggestions: [] };
+            return {
+              suggestions: cmd.params
+                .filter((p) => !p.positional)
+                .map((p) => {
+                  const name = p.numbered ? `${p.name}1` : p.name;
+                  return {
+                    label: `-${name}`,
+                    kind: monaco.languages.CompletionItemKind.Property,
+                    detail: p.values ? p.values.join("|") : p.type,
+                    documentation: p.description,
+                    insertText: p.type === "flag" ? name : `${name}=`,
+                    range,
+                  };
+                }),
+            };
+          },
+        });
+      })
+      .catch(() => {});
+  };
+
   const handleFileUpload = (e) => {
     const file = e.target.files[0];
     if (file && file.name.endsWith(".smia")) {
@@ -40,16 +95,20 @@ function App() {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
+          ...(usuarioActual?.token && {
+            Authorization: `Bearer ${usuarioActual.token}`,
+          }),
         },
         body: JSON.stringify({
           command: commandInput,
-          user: usuarioActual?.username,
-          partitionId: usuarioActual?.partitionId,
         }),
       });
 
       const result = await response.json();
       setOutput(result.output);
+      if (response.status === 401) {
+        setUsuarioActual(null);
+      }
     } catch (error) {
       setOutput("Error al comunicarse con el backend.");
     }
@@ -61,6 +120,12 @@ function App() {
   };
 
   const handleLogout = () => {
+    if (usuarioActual?.token) {
+      fetch("http://localhost:3001/logout", {
+        method: "POST",
+        headers: { Authorization: `Bearer ${usuarioActual.token}` },
+      }).catch(() => {});
+    }
     setUsuarioActual(null);
     setCommandInput("");
     setOutput("");
@@ -114,6 +179,7 @@ function App() {
                     theme="hc-black"
                     value={commandInput}
                     onChange={(value) => setCommandInput(value)}
+                    onMount={handleEditorMount}
                     options={{
                       minimap: { enabled: false },
                       fontSize: 14,
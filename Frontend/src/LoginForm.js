@@ -24,8 +24,11 @@ function LoginForm({ onLogin }) {
       });
   
       if (response.ok) {
+        const data = await response.json();
         alert("Inicio de sesión exitoso");
-        onLogin({ username, partitionId, rememberUser });
+        onLogin({ username, partitionId, rememberUser, token: data.token });
+      } else if (response.status === 429) {
+        alert(await response.text());
       } else {
         alert("Usuario o contraseña incorrectos o partición no montada");
       }